@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	openshiftApps "github.com/openshift/client-go/apps/clientset/versioned"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/bgruszka/kuota-calc/internal/calc"
+	"github.com/bgruszka/kuota-calc/internal/cluster"
+)
+
+const stdinSource = "STDIN"
+
+// fileExtensions are the extensions of files read when walking a directory, just like kubectl does.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var fileExtensions = []string{".json", ".yaml", ".yml"}
+
+// readCluster lists all supported resources of the namespace given by --namespace (or the current context)
+// or of all namespaces if --all-namespaces is set.
+func (opts *KuotaCalcOpts) readCluster(ctx context.Context) ([]calc.ResourceObject, error) {
+	if !opts.allNamespaces {
+		namespace, _, err := opts.configFlags.ToRawKubeConfigLoader().Namespace()
+		if err != nil {
+			return nil, fmt.Errorf("reading namespace: %w", err)
+		}
+
+		opts.namespace = namespace
+	}
+
+	restConfig, err := opts.configFlags.ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("reading kubeconfig: %w", err)
+	}
+
+	kubernetesClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating kubernetes client: %w", err)
+	}
+
+	openshiftClient, err := openshiftApps.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating openshift client: %w", err)
+	}
+
+	lister := cluster.Lister{Kubernetes: kubernetesClient, OpenShift: openshiftClient}
+
+	return lister.List(ctx, opts.namespace)
+}
+
+// readInputs reads all objects from the files given by --filename, or from stdin if none are given.
+func (opts *KuotaCalcOpts) readInputs() ([]calc.ResourceObject, error) {
+	if len(opts.filenames) == 0 {
+		return opts.readAndConvertYAML(opts.In, "")
+	}
+
+	objects := []calc.ResourceObject{}
+
+	for _, filename := range opts.filenames {
+		if filename == "-" {
+			stdinObjects, err := opts.readAndConvertYAML(opts.In, stdinSource)
+			if err != nil {
+				return nil, err
+			}
+
+			objects = append(objects, stdinObjects...)
+
+			continue
+		}
+
+		paths, err := opts.expandPath(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range paths {
+			fileObjects, err := opts.readFile(path)
+			if err != nil {
+				return nil, err
+			}
+
+			objects = append(objects, fileObjects...)
+		}
+	}
+
+	return objects, nil
+}
+
+// expandPath returns the path itself if it is a file. If it is a directory, all files with a known extension
+// in it are returned, including the ones in subdirectories if --recursive is set.
+func (opts *KuotaCalcOpts) expandPath(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading input: %w", err)
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	paths := []string{}
+
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if p != path && !opts.recursive {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if slices.Contains(fileExtensions, filepath.Ext(p)) {
+			paths = append(paths, p)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading input: %w", err)
+	}
+
+	return paths, nil
+}
+
+func (opts *KuotaCalcOpts) readFile(path string) ([]calc.ResourceObject, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading input: %w", err)
+	}
+	defer f.Close()
+
+	objects, err := opts.readAndConvertYAML(f, path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/bgruszka/kuota-calc/internal/calc"
+)
+
+func podYAML(name string) string {
+	return fmt.Sprintf("---\napiVersion: v1\nkind: Pod\nmetadata:\n  name: %s\nspec:\n  containers:\n  - name: app\n    image: app\n", name)
+}
+
+func podJSON(name string) string {
+	return fmt.Sprintf(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": %q}, "spec": {"containers": [{"name": "app", "image": "app"}]}}`, name)
+}
+
+// inputDir creates a directory with manifests in nested directories and files with other extensions.
+func inputDir(t *testing.T) string {
+	t.Helper()
+
+	r := require.New(t)
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"a.yaml":                podYAML("a"),
+		"b.yml":                 podYAML("b"),
+		"c.json":                podJSON("c"),
+		"notes.txt":             "not a manifest",
+		"kustomization.yaml.j2": "{{ not a manifest }}",
+		"sub/d.yaml":            podYAML("d"),
+		"sub/README.md":         "# not a manifest",
+		"sub/deeper/e.yaml":     podYAML("e"),
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		r.NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+		r.NoError(os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	return dir
+}
+
+func objectNames(r *require.Assertions, objects []calc.ResourceObject) []string {
+	names := []string{}
+
+	for _, obj := range objects {
+		accessor, err := meta.Accessor(obj.Object)
+		r.NoError(err)
+
+		names = append(names, accessor.GetName())
+	}
+
+	return names
+}
+
+func objectSources(objects []calc.ResourceObject) []string {
+	sources := []string{}
+	for _, obj := range objects {
+		sources = append(sources, obj.Source)
+	}
+
+	return sources
+}
+
+func TestExpandPath(t *testing.T) {
+	var tests = []struct {
+		name      string
+		path      string
+		recursive bool
+		expected  []string
+		err       bool
+	}{
+		{
+			name:     "file",
+			path:     "sub/d.yaml",
+			expected: []string{"sub/d.yaml"},
+		},
+		{
+			name:     "file with other extension",
+			path:     "notes.txt",
+			expected: []string{"notes.txt"},
+		},
+		{
+			name:     "directory",
+			path:     ".",
+			expected: []string{"a.yaml", "b.yml", "c.json"},
+		},
+		{
+			name:      "recursive directory",
+			path:      ".",
+			recursive: true,
+			expected:  []string{"a.yaml", "b.yml", "c.json", "sub/d.yaml", "sub/deeper/e.yaml"},
+		},
+		{
+			name:      "recursive subdirectory",
+			path:      "sub",
+			recursive: true,
+			expected:  []string{"sub/d.yaml", "sub/deeper/e.yaml"},
+		},
+		{
+			name: "missing",
+			path: "missing.yaml",
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+
+			dir := inputDir(t)
+			opts := KuotaCalcOpts{recursive: test.recursive}
+
+			paths, err := opts.expandPath(filepath.Join(dir, test.path))
+			if test.err {
+				r.Error(err)
+				return
+			}
+
+			r.NoError(err)
+
+			expected := []string{}
+			for _, path := range test.expected {
+				expected = append(expected, filepath.Join(dir, path))
+			}
+
+			r.Equal(expected, paths)
+		})
+	}
+}
+
+func TestReadInputs(t *testing.T) {
+	var tests = []struct {
+		name      string
+		filenames []string
+		recursive bool
+		stdin     string
+		names     []string
+		sources   []string
+		err       bool
+	}{
+		{
+			name:    "stdin without filenames",
+			stdin:   podYAML("stdin"),
+			names:   []string{"stdin"},
+			sources: []string{""},
+		},
+		{
+			name:      "repeated filenames",
+			filenames: []string{"c.json", "a.yaml", "sub/d.yaml"},
+			names:     []string{"c", "a", "d"},
+			sources:   []string{"c.json", "a.yaml", "sub/d.yaml"},
+		},
+		{
+			name:      "directory and stdin",
+			filenames: []string{"sub", "-"},
+			stdin:     podYAML("stdin") + podYAML("stdin-2"),
+			names:     []string{"d", "stdin", "stdin-2"},
+			sources:   []string{"sub/d.yaml", stdinSource, stdinSource},
+		},
+		{
+			name:      "recursive directory",
+			filenames: []string{"."},
+			recursive: true,
+			names:     []string{"a", "b", "c", "d", "e"},
+			sources:   []string{"a.yaml", "b.yml", "c.json", "sub/d.yaml", "sub/deeper/e.yaml"},
+		},
+		{
+			name:      "file with other extension",
+			filenames: []string{"notes.txt"},
+			err:       true,
+		},
+		{
+			name:      "missing file",
+			filenames: []string{"a.yaml", "missing.yaml"},
+			err:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+
+			dir := inputDir(t)
+
+			filenames := []string{}
+			for _, filename := range test.filenames {
+				if filename != "-" {
+					filename = filepath.Join(dir, filename)
+				}
+
+				filenames = append(filenames, filename)
+			}
+
+			opts := KuotaCalcOpts{
+				IOStreams: genericclioptions.IOStreams{In: strings.NewReader(test.stdin), Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}},
+				filenames: filenames,
+				recursive: test.recursive,
+			}
+
+			objects, err := opts.readInputs()
+			if test.err {
+				r.Error(err)
+				return
+			}
+
+			r.NoError(err)
+			r.Equal(test.names, objectNames(r, objects))
+
+			sources := []string{}
+			for _, source := range test.sources {
+				if source != "" && source != stdinSource {
+					source = filepath.Join(dir, source)
+				}
+
+				sources = append(sources, source)
+			}
+
+			r.Equal(sources, objectSources(objects))
+		})
+	}
+}
@@ -3,50 +3,124 @@ package cmd
 
 import (
 	"bufio"
+	"cmp"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"runtime"
+	"slices"
+	"strings"
 	"text/tabwriter"
+	"time"
 
-	appsv1 "k8s.io/api/apps/v1"
-	v2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/json"
 
 	"github.com/bgruszka/kuota-calc/internal/calc"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
 )
 
 const (
+	groupByNamespace = "namespace"
+
 	kuotaCalcExample = `    # provide a simple/complex deployment by piping it to kuota-calc (used as kubectl plugin)
     cat deployment.yaml | kubectl %[1]s
 
     # do the same, calling the binary directly with detailed output
-    cat deployment.yaml | %[1]s --detailed`
+    cat deployment.yaml | %[1]s --detailed
+
+    # calculate the quota of the workloads deployed in a namespace
+    kubectl %[1]s -n my-namespace --detailed
+
+    # calculate one total per namespace for the whole cluster
+    kubectl %[1]s --all-namespaces
+
+    # calculate a separate total for each namespace of a multi-namespace dump
+    cat dump.yaml | %[1]s --group-by namespace --detailed
+
+    # read all manifests in a directory tree, showing the file each resource comes from
+    %[1]s -f manifests/ --recursive --detailed
+
+    # apply the defaults of the namespace LimitRange to containers without requests or limits
+    cat deployment.yaml | %[1]s --limit-range-file limitrange.yaml
+
+    # calculate workloads scaled by a HorizontalPodAutoscaler with its minReplicas
+    cat deployment.yaml | %[1]s --hpa-mode min --detailed
+
+    # calculate DaemonSets with the nodes they are scheduled to
+    cat daemonset.yaml | %[1]s --node-file nodes.yaml --detailed
+
+    # calculate CronJobs whose jobs run for 45 minutes, overlapping if they are scheduled more often
+    cat cronjob.yaml | %[1]s --job-duration 45m --detailed
+
+    # calculate Knative Services with the queue-proxy resources configured for the cluster
+    cat service.yaml | %[1]s --knative-config-file config-deployment.yaml --detailed
+
+    # calculate the pods running in a namespace instead of their Deployments, Jobs, etc.
+    kubectl get all -n my-namespace -o yaml | %[1]s --live-pods --detailed
+
+    # check whether the deployment fits into an existing ResourceQuota, exits with code 2 if not
+    cat deployment.yaml | %[1]s --check --quota-file quota.yaml
+
+    # generate a ResourceQuota manifest and apply it
+    cat deployment.yaml | %[1]s --resource-quota --resource-quota-namespace my-namespace | kubectl apply -f -`
 )
 
+// ErrQuotaExceeded is returned in check mode if the calculated resources exceed a ResourceQuota.
+var ErrQuotaExceeded = errors.New("resource quota exceeded")
+
 type jsonResource struct {
-	Version       string `json:"version"`
-	Kind          string `json:"kind"`
-	Name          string `json:"name"`
-	Replicas      int32  `json:"replicas"`
-	Strategy      string `json:"strategy"`
-	MaxReplicas   int32  `json:"maxReplicas"`
-	CPURequest    string `json:"CPURequest"`
-	CPULimit      string `json:"CPULimit"`
-	MemoryRequest string `json:"memoryRequest"`
-	MemoryLimit   string `json:"memoryLimit"`
-	IsHPA         bool   `json:"isHPA"`
+	Version       string            `json:"version"`
+	Kind          string            `json:"kind"`
+	Name          string            `json:"name"`
+	Namespace     string            `json:"namespace,omitempty"`
+	Source        string            `json:"source,omitempty"`
+	ResourcesFrom string            `json:"resourcesFrom,omitempty"`
+	Replicas      int32             `json:"replicas"`
+	Strategy      string            `json:"strategy"`
+	MaxReplicas   int32             `json:"maxReplicas"`
+	CPURequest    string            `json:"CPURequest"`
+	CPULimit      string            `json:"CPULimit"`
+	MemoryRequest string            `json:"memoryRequest"`
+	MemoryLimit   string            `json:"memoryLimit"`
+	Requests      map[string]string `json:"requests"`
+	Limits        map[string]string `json:"limits"`
+	Storage       map[string]string `json:"storage,omitempty"`
+	IsHPA         bool              `json:"isHPA"`
+	HPA           *jsonHpa          `json:"hpa,omitempty"`
+}
+
+// jsonHpa contains the usage of a workload scaled by a HorizontalPodAutoscaler at its min and max replicas.
+type jsonHpa struct {
+	Min jsonHpaUsage `json:"min"`
+	Max jsonHpaUsage `json:"max"`
+}
+
+type jsonHpaUsage struct {
+	Replicas    int32             `json:"replicas"`
+	MaxReplicas int32             `json:"maxReplicas"`
+	Requests    map[string]string `json:"requests"`
+	Limits      map[string]string `json:"limits"`
 }
 
 type jsonOutputTotal struct {
-	CPURequest    string `json:"CPURequest"`
-	CPULimit      string `json:"CPULimit"`
-	MemoryRequest string `json:"memoryRequest"`
-	MemoryLimit   string `json:"memoryLimit"`
+	CPURequest    string            `json:"CPURequest"`
+	CPULimit      string            `json:"CPULimit"`
+	MemoryRequest string            `json:"memoryRequest"`
+	MemoryLimit   string            `json:"memoryLimit"`
+	Requests      map[string]string `json:"requests"`
+	Limits        map[string]string `json:"limits"`
+	Storage       map[string]string `json:"storage,omitempty"`
+	ObjectCounts  map[string]string `json:"objectCounts"`
 }
 
 type jsonOutput struct {
@@ -54,9 +128,26 @@ type jsonOutput struct {
 	Total     jsonOutputTotal `json:"total"`
 }
 
+type jsonNamespaceOutput struct {
+	Namespace string `json:"namespace"`
+	jsonOutput
+}
+
+type jsonGroupedOutput struct {
+	Namespaces []jsonNamespaceOutput `json:"namespaces"`
+}
+
+// namespaceGroup holds the objects of a single namespace and their calculated usage.
+type namespaceGroup struct {
+	namespace string
+	objects   []calc.ResourceObject
+	usage     []*calc.ResourceUsage
+}
+
 // KuotaCalcOpts holds all command options.
 type KuotaCalcOpts struct {
 	genericclioptions.IOStreams
+	configFlags *genericclioptions.ConfigFlags
 
 	// flags
 	debug                              bool
@@ -65,7 +156,35 @@ type KuotaCalcOpts struct {
 	maxRollouts                        int
 	json                               bool
 	suppressWarningForUnregisteredKind bool
-	// files    []string
+	resourceQuota                      bool
+	resourceQuotaName                  string
+	resourceQuotaNamespace             string
+	check                              bool
+	quotaFile                          string
+	limitRangeFile                     string
+	filenames                          []string
+	recursive                          bool
+	allNamespaces                      bool
+	groupBy                            string
+	hpaMode                            string
+	livePods                           bool
+	nodeCount                          int32
+	nodeFile                           string
+	jobDuration                        time.Duration
+	knativeConfigFile                  string
+
+	// live is set if the resources are read from the cluster instead of files
+	live bool
+	// namespace is the namespace the resources are read from in live mode, empty for all namespaces
+	namespace string
+	// limitRanges are read from --limit-range-file and apply to all groups
+	limitRanges []*v1.LimitRange
+	// runtimeClasses are cluster scoped and apply to all groups
+	runtimeClasses []*nodev1.RuntimeClass
+	// nodes are read from the input and --node-file, DaemonSets of all groups are scheduled to them
+	nodes []*v1.Node
+	// queueProxyResources are the resources of the queue-proxy sidecar of Knative Services in the cluster
+	queueProxyResources *v1.ResourceRequirements
 
 	versionInfo *Version
 }
@@ -74,6 +193,7 @@ type KuotaCalcOpts struct {
 func NewKuotaCalcCmd(version *Version, streams genericclioptions.IOStreams) *cobra.Command {
 	opts := KuotaCalcOpts{
 		IOStreams:   streams,
+		configFlags: genericclioptions.NewConfigFlags(true),
 		versionInfo: version,
 	}
 
@@ -82,12 +202,33 @@ func NewKuotaCalcCmd(version *Version, streams genericclioptions.IOStreams) *cob
 		Short:        "Calculate the resource quota needs of your deployment(s).",
 		Example:      fmt.Sprintf(kuotaCalcExample, "kuota-calc"),
 		SilenceUsage: true,
-		RunE: func(_ *cobra.Command, _ []string) error {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			if opts.version {
 				return opts.printVersion()
 			}
 
-			return opts.run()
+			if opts.groupBy != "" && opts.groupBy != groupByNamespace {
+				return fmt.Errorf("unsupported --group-by value %q, supported: %s", opts.groupBy, groupByNamespace)
+			}
+
+			switch calc.HpaMode(opts.hpaMode) {
+			case calc.HpaModeMin, calc.HpaModeMax, calc.HpaModeSpec:
+			default:
+				return fmt.Errorf("unsupported --hpa-mode value %q, supported: min, max, spec", opts.hpaMode)
+			}
+
+			if opts.jobDuration < 0 {
+				return fmt.Errorf("invalid --job-duration value %s, must not be negative", opts.jobDuration)
+			}
+
+			if opts.nodeCount < 0 {
+				return fmt.Errorf("invalid --nodes value %d, must not be negative", opts.nodeCount)
+			}
+
+			// without files, an explicit namespace selects the workloads deployed in the cluster
+			opts.live = len(opts.filenames) == 0 && (cmd.Flags().Changed("namespace") || opts.allNamespaces)
+
+			return opts.run(cmd.Context())
 		},
 	}
 
@@ -97,6 +238,26 @@ func NewKuotaCalcCmd(version *Version, streams genericclioptions.IOStreams) *cob
 	cmd.Flags().IntVar(&opts.maxRollouts, "max-rollouts", -1, "limit the simultaneous rollout to the n most expensive rollouts per resource")
 	cmd.Flags().BoolVar(&opts.json, "json", false, "output to json")
 	cmd.Flags().BoolVar(&opts.suppressWarningForUnregisteredKind, "suppressWarningForUnregisteredKind", false, "suppress warning for unregistered kind")
+	cmd.Flags().BoolVar(&opts.resourceQuota, "resource-quota", false, "output a ResourceQuota manifest (yaml, or json if --json is set)")
+	cmd.Flags().StringVar(&opts.resourceQuotaName, "resource-quota-name", "kuota-calc", "name of the generated ResourceQuota")
+	cmd.Flags().StringVar(&opts.resourceQuotaNamespace, "resource-quota-namespace", "", "namespace of the generated ResourceQuota")
+	cmd.Flags().BoolVar(&opts.check, "check", false, "check the total against the ResourceQuota(s) in the input or --quota-file and fail if exceeded")
+	cmd.Flags().StringVar(&opts.quotaFile, "quota-file", "", "file containing the ResourceQuota(s) used by --check")
+	cmd.Flags().StringVar(&opts.limitRangeFile, "limit-range-file", "", "file containing LimitRange(s) whose defaults are applied to the containers")
+	cmd.Flags().StringSliceVarP(&opts.filenames, "filename", "f", nil, "files or directories containing the resources, - reads from stdin (default: stdin)")
+	cmd.Flags().BoolVarP(&opts.recursive, "recursive", "R", false, "process the directories given by --filename recursively")
+	cmd.Flags().BoolVarP(&opts.allNamespaces, "all-namespaces", "A", false, "read the workloads of all namespaces from the cluster, calculating a total per namespace")
+	cmd.Flags().StringVar(&opts.groupBy, "group-by", "", "group the resources and totals, supported: namespace")
+	cmd.Flags().StringVar(&opts.hpaMode, "hpa-mode", string(calc.HpaModeMax),
+		"replicas of workloads scaled by a HorizontalPodAutoscaler, supported: min, max (HPA replicas) and spec (workload replicas)")
+	cmd.Flags().Int32Var(&opts.nodeCount, "nodes", 0, "number of nodes DaemonSets are scheduled to, if no Node objects are given")
+	cmd.Flags().StringVar(&opts.nodeFile, "node-file", "", "file containing the Node(s) DaemonSets are scheduled to by their node selection and tolerations")
+	cmd.Flags().DurationVar(&opts.jobDuration, "job-duration", 0,
+		"known runtime of the jobs of CronJobs, used to estimate overlapping jobs (overrides activeDeadlineSeconds)")
+	cmd.Flags().StringVar(&opts.knativeConfigFile, "knative-config-file", "",
+		"file containing the config-deployment ConfigMap of Knative Serving with the queue-sidecar-* resources of the queue-proxy")
+	cmd.Flags().BoolVar(&opts.livePods, "live-pods", false, "calculate the pods of the input instead of their owners, e.g. Deployments or Jobs")
+	opts.configFlags.AddFlags(cmd.Flags())
 
 	return cmd
 }
@@ -112,29 +273,117 @@ func (opts *KuotaCalcOpts) printVersion() error {
 	return nil
 }
 
-func (opts *KuotaCalcOpts) run() error {
-	summary, err := opts.readAndConvertYAML()
+func (opts *KuotaCalcOpts) run(ctx context.Context) error {
+	var (
+		objects []calc.ResourceObject
+		err     error
+	)
+
+	if opts.live {
+		objects, err = opts.readCluster(ctx)
+	} else {
+		objects, err = opts.readInputs()
+	}
+
 	if err != nil {
 		return err
 	}
 
-	if !opts.json {
+	if err := opts.readClusterConfig(objects); err != nil {
+		return err
+	}
+
+	groups := opts.groupObjects(objects)
+
+	for _, group := range groups {
+		group.usage, err = opts.processObjects(group.objects)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.check {
+		return opts.checkQuotas(groups)
+	}
+
+	if opts.resourceQuota {
+		return opts.printResourceQuota(groups)
+	}
+
+	if opts.json {
+		opts.printJSON(groups)
+
+		return nil
+	}
+
+	for i, group := range groups {
+		if opts.grouped() {
+			if i > 0 {
+				_, _ = fmt.Fprintln(opts.Out)
+			}
+
+			_, _ = fmt.Fprintf(opts.Out, "Namespace: %s\n\n", displayNamespace(group.namespace))
+		}
+
 		if opts.detailed {
-			opts.printDetailed(summary)
+			opts.printDetailed(group.objects, group.usage)
 		} else {
-			opts.printSummary(summary)
+			opts.printSummary(group.usage)
 		}
-	} else {
-		opts.printJSON(summary)
 	}
 
 	return nil
 }
 
-func (opts *KuotaCalcOpts) readAndConvertYAML() ([]*calc.ResourceUsage, error) {
-	yamlReader := yaml.NewYAMLReader(bufio.NewReader(opts.In))
+// grouped reports whether a separate total is calculated for each namespace.
+func (opts *KuotaCalcOpts) grouped() bool {
+	return opts.groupBy == groupByNamespace || (opts.live && opts.allNamespaces)
+}
+
+// groupObjects splits the objects by namespace, sorted by namespace name. If the output is not grouped,
+// all objects are returned in a single group.
+func (opts *KuotaCalcOpts) groupObjects(objects []calc.ResourceObject) []*namespaceGroup {
+	if !opts.grouped() {
+		return []*namespaceGroup{{objects: objects}}
+	}
+
+	groups := []*namespaceGroup{}
+	index := map[string]*namespaceGroup{}
+
+	for _, obj := range objects {
+		namespace := ""
+		if accessor, err := meta.Accessor(obj.Object); err == nil {
+			namespace = accessor.GetNamespace()
+		}
+
+		group, ok := index[namespace]
+		if !ok {
+			group = &namespaceGroup{namespace: namespace}
+			index[namespace] = group
+			groups = append(groups, group)
+		}
+
+		group.objects = append(group.objects, obj)
+	}
+
+	slices.SortFunc(groups, func(a, b *namespaceGroup) int {
+		return cmp.Compare(a.namespace, b.namespace)
+	})
+
+	return groups
+}
+
+func displayNamespace(namespace string) string {
+	if namespace == "" {
+		return "<none>"
+	}
+
+	return namespace
+}
+
+func (opts *KuotaCalcOpts) readAndConvertYAML(in io.Reader, source string) ([]calc.ResourceObject, error) {
+	yamlReader := yaml.NewYAMLReader(bufio.NewReader(in))
 
-	hpas := []*v2.HorizontalPodAutoscaler{}
 	objects := []calc.ResourceObject{}
 
 	for {
@@ -147,33 +396,71 @@ func (opts *KuotaCalcOpts) readAndConvertYAML() ([]*calc.ResourceUsage, error) {
 			return nil, fmt.Errorf("reading input: %w", err)
 		}
 
-		runtimeObject, kind, version, err := calc.ConvertToRuntimeObjectFromYaml(data, opts.suppressWarningForUnregisteredKind)
+		documentObjects, err := opts.convertDocument(data, source)
 		if err != nil {
-			return nil, fmt.Errorf("converting to runtime object: %w", err)
+			return nil, err
 		}
 
-		horizontalPodAutoscaler, ok := runtimeObject.(*v2.HorizontalPodAutoscaler)
-		if ok {
-			hpas = append(hpas, horizontalPodAutoscaler)
+		objects = append(objects, documentObjects...)
+	}
+
+	return objects, nil
+}
+
+// convertDocument converts a single yaml or json document to its objects. The items of a List, as returned by
+// kubectl get -o yaml, are converted one by one.
+func (opts *KuotaCalcOpts) convertDocument(data []byte, source string) ([]calc.ResourceObject, error) {
+	runtimeObject, kind, version, err := calc.ConvertToRuntimeObjectFromYaml(data, opts.suppressWarningForUnregisteredKind)
+	if err != nil {
+		return nil, fmt.Errorf("converting to runtime object: %w", err)
+	}
+
+	list, ok := runtimeObject.(*v1.List)
+	if !ok {
+		return []calc.ResourceObject{{Object: runtimeObject, Kind: *kind, Version: *version, Source: source}}, nil
+	}
+
+	objects := []calc.ResourceObject{}
+
+	for _, item := range list.Items {
+		itemObjects, err := opts.convertDocument(item.Raw, source)
+		if err != nil {
+			return nil, err
 		}
 
-		objects = append(objects, calc.ResourceObject{Object: runtimeObject, Kind: *kind, Version: *version})
+		objects = append(objects, itemObjects...)
 	}
 
-	return opts.processObjects(objects, hpas)
+	return objects, nil
 }
 
-func (opts *KuotaCalcOpts) processObjects(objects []calc.ResourceObject, hpas []*v2.HorizontalPodAutoscaler) ([]*calc.ResourceUsage, error) {
+func (opts *KuotaCalcOpts) processObjects(objects []calc.ResourceObject) ([]*calc.ResourceUsage, error) {
 	summary := []*calc.ResourceUsage{}
+	limitRanges := append(objectsOf[*v1.LimitRange](objects), opts.limitRanges...)
+
+	for _, warning := range append(calc.LinkHorizontalPodAutoscalers(objects), calc.LinkWorkloadRefs(objects)...) {
+		_, _ = fmt.Fprintf(opts.ErrOut, "WARNING: %s\n", warning)
+	}
+
+	objects, skipped := calc.DeduplicateOwned(objects, opts.livePods)
+	if opts.debug {
+		for _, message := range skipped {
+			_, _ = fmt.Fprintf(opts.Out, "DEBUG: %s\n", message)
+		}
+	}
 
 	for _, obj := range objects {
-		deployment, ok := obj.Object.(*appsv1.Deployment)
-		if ok {
-			for _, hpa := range hpas {
-				if hpa.Spec.ScaleTargetRef.Name == deployment.Name {
-					obj.LinkedObject = hpa
-				}
-			}
+		var violations []string
+
+		obj.HpaMode = calc.HpaMode(opts.hpaMode)
+		obj.Nodes = opts.nodes
+		obj.NodeCount = opts.nodeCount
+		obj.JobDuration = opts.jobDuration
+		obj.QueueProxy = opts.queueProxyResources
+
+		obj, violations = calc.ApplyLimitRanges(calc.ApplyRuntimeClasses(obj, opts.runtimeClasses), limitRanges)
+		for _, violation := range violations {
+			_, _ = fmt.Fprintf(opts.ErrOut, "WARNING: LimitRange violation: %s\n", violation)
 		}
 
 		usage, err := calc.ResourceQuotaFromYaml(obj)
@@ -189,13 +476,191 @@ func (opts *KuotaCalcOpts) processObjects(objects []calc.ResourceObject, hpas []
 			return nil, err
 		}
 
+		usage.Details.Source = obj.Source
 		summary = append(summary, usage)
 	}
 
 	return summary, nil
 }
 
-func (opts *KuotaCalcOpts) printJSON(usage []*calc.ResourceUsage) {
+// readClusterConfig reads the configuration of the cluster that applies to all groups from the input and the
+// --limit-range-file, --node-file and --knative-config-file flags.
+func (opts *KuotaCalcOpts) readClusterConfig(objects []calc.ResourceObject) error {
+	if opts.limitRangeFile != "" {
+		limitRangeObjects, err := opts.readFile(opts.limitRangeFile)
+		if err != nil {
+			return err
+		}
+
+		opts.limitRanges = objectsOf[*v1.LimitRange](limitRangeObjects)
+	}
+
+	opts.runtimeClasses = objectsOf[*nodev1.RuntimeClass](objects)
+	opts.nodes = objectsOf[*v1.Node](objects)
+
+	if opts.nodeFile != "" {
+		nodeObjects, err := opts.readFile(opts.nodeFile)
+		if err != nil {
+			return err
+		}
+
+		opts.nodes = append(opts.nodes, objectsOf[*v1.Node](nodeObjects)...)
+	}
+
+	configMaps := objectsOf[*v1.ConfigMap](objects)
+
+	if opts.knativeConfigFile != "" {
+		configObjects, err := opts.readFile(opts.knativeConfigFile)
+		if err != nil {
+			return err
+		}
+
+		configMaps = append(configMaps, objectsOf[*v1.ConfigMap](configObjects)...)
+	}
+
+	var err error
+
+	opts.queueProxyResources, err = calc.QueueProxyResources(configMaps)
+
+	return err
+}
+
+// objectsOf returns all objects of the given type, e.g. all LimitRanges.
+func objectsOf[T any](objects []calc.ResourceObject) []T {
+	result := []T{}
+
+	for _, obj := range objects {
+		if typed, ok := obj.Object.(T); ok {
+			result = append(result, typed)
+		}
+	}
+
+	return result
+}
+
+func (opts *KuotaCalcOpts) checkQuotas(groups []*namespaceGroup) error {
+	objects := []calc.ResourceObject{}
+	quotas := []*v1.ResourceQuota{}
+
+	for _, group := range groups {
+		objects = append(objects, group.objects...)
+	}
+
+	if opts.quotaFile != "" {
+		quotaObjects, err := opts.readFile(opts.quotaFile)
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, quotaObjects...)
+	}
+
+	for _, obj := range objects {
+		if quota, ok := obj.Object.(*v1.ResourceQuota); ok {
+			quotas = append(quotas, quota)
+		}
+	}
+
+	if len(quotas) == 0 {
+		return errors.New("check: no ResourceQuota found in input or quota file")
+	}
+
+	exceeded := false
+	printed := 0
+
+	for _, group := range groups {
+		needed := calc.QuotaUsage(opts.maxRollouts, group.objects, group.usage)
+
+		for _, quota := range quotas {
+			// quotas without a namespace apply to every namespace
+			if opts.grouped() && quota.Namespace != "" && quota.Namespace != group.namespace {
+				continue
+			}
+
+			if printed > 0 {
+				_, _ = fmt.Fprintln(opts.Out)
+			}
+
+			printed++
+
+			if opts.grouped() {
+				_, _ = fmt.Fprintf(opts.Out, "ResourceQuota %s/%s\n", displayNamespace(group.namespace), quota.Name)
+			} else {
+				_, _ = fmt.Fprintf(opts.Out, "ResourceQuota %s\n", quota.Name)
+			}
+
+			if opts.checkQuota(quota, needed) {
+				exceeded = true
+			}
+		}
+	}
+
+	if exceeded {
+		return ErrQuotaExceeded
+	}
+
+	return nil
+}
+
+// checkQuota prints the comparison of a single quota with the needed resources and reports whether the
+// quota is exceeded.
+func (opts *KuotaCalcOpts) checkQuota(quota *v1.ResourceQuota, needed v1.ResourceList) bool {
+	exceeded := false
+
+	w := tabwriter.NewWriter(opts.Out, 0, 0, 4, ' ', tabwriter.TabIndent)
+	_, _ = fmt.Fprintf(w, "Resource\tHard\tNeeded\tHeadroom\tStatus\t\n")
+
+	for _, check := range calc.CheckQuota(quota, needed) {
+		status := "ok"
+		if check.Exceeded {
+			status = "exceeded"
+			exceeded = true
+		}
+
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n",
+			check.Name,
+			check.Hard.String(),
+			check.Needed.String(),
+			check.Headroom.String(),
+			status,
+		)
+	}
+
+	if err := w.Flush(); err != nil {
+		_, _ = fmt.Fprintf(opts.Out, "printing quota check to tabwriter failed: %v\n", err)
+	}
+
+	return exceeded
+}
+
+func (opts *KuotaCalcOpts) printJSON(groups []*namespaceGroup) {
+	var output any
+
+	if opts.grouped() {
+		grouped := jsonGroupedOutput{Namespaces: []jsonNamespaceOutput{}}
+
+		for _, group := range groups {
+			grouped.Namespaces = append(grouped.Namespaces, jsonNamespaceOutput{
+				Namespace:  group.namespace,
+				jsonOutput: opts.jsonOutput(group.objects, group.usage),
+			})
+		}
+
+		output = grouped
+	} else {
+		output = opts.jsonOutput(groups[0].objects, groups[0].usage)
+	}
+
+	marshaled, err := json.Marshal(output)
+
+	if err != nil {
+		log.Fatalf("marshaling error: %s", err)
+	}
+
+	_, _ = fmt.Fprintln(opts.Out, string(marshaled))
+}
+
+func (opts *KuotaCalcOpts) jsonOutput(objects []calc.ResourceObject, usage []*calc.ResourceUsage) jsonOutput {
 	jsonOutput := jsonOutput{}
 
 	for _, u := range usage {
@@ -208,37 +673,187 @@ func (opts *KuotaCalcOpts) printJSON(usage []*calc.ResourceUsage) {
 			Version:       u.Details.Version,
 			Kind:          u.Details.Kind,
 			Name:          u.Details.Name,
+			Namespace:     u.Details.Namespace,
+			Source:        u.Details.Source,
+			ResourcesFrom: u.Details.ResourcesFrom,
 			Replicas:      u.Details.Replicas,
 			Strategy:      u.Details.Strategy,
 			MaxReplicas:   u.Details.MaxReplicas,
-			CPURequest:    u.RolloutResources.CPUMin.String(),
-			CPULimit:      u.RolloutResources.CPUMax.String(),
-			MemoryRequest: u.RolloutResources.MemoryMin.String(),
-			MemoryLimit:   u.RolloutResources.MemoryMax.String(),
+			CPURequest:    u.RolloutResources.Requests.Cpu().String(),
+			CPULimit:      u.RolloutResources.Limits.Cpu().String(),
+			MemoryRequest: u.RolloutResources.Requests.Memory().String(),
+			MemoryLimit:   u.RolloutResources.Limits.Memory().String(),
+			Requests:      quantityStrings(u.RolloutResources.Requests),
+			Limits:        quantityStrings(u.RolloutResources.Limits),
+			Storage:       quantityStrings(u.Storage),
 			IsHPA:         isHpa,
+			HPA:           hpaOutput(u),
 		})
 	}
 
 	totalResources := calc.Total(opts.maxRollouts, usage)
 
-	jsonOutput.Total.CPURequest = totalResources.CPUMin.String()
-	jsonOutput.Total.CPULimit = totalResources.CPUMax.String()
-	jsonOutput.Total.MemoryRequest = totalResources.MemoryMin.String()
-	jsonOutput.Total.MemoryLimit = totalResources.MemoryMax.String()
+	jsonOutput.Total.CPURequest = totalResources.Requests.Cpu().String()
+	jsonOutput.Total.CPULimit = totalResources.Limits.Cpu().String()
+	jsonOutput.Total.MemoryRequest = totalResources.Requests.Memory().String()
+	jsonOutput.Total.MemoryLimit = totalResources.Limits.Memory().String()
+	jsonOutput.Total.Requests = quantityStrings(totalResources.Requests)
+	jsonOutput.Total.Limits = quantityStrings(totalResources.Limits)
+	jsonOutput.Total.Storage = quantityStrings(calc.TotalStorage(usage))
+	jsonOutput.Total.ObjectCounts = quantityStrings(calc.TotalObjectCount(objects, usage))
 
-	marshaled, err := json.Marshal(jsonOutput)
+	return jsonOutput
+}
 
-	if err != nil {
-		log.Fatalf("marshaling error: %s", err)
+// quantityStrings converts a ResourceList to a map of resource names to quantity strings.
+func quantityStrings(list v1.ResourceList) map[string]string {
+	result := map[string]string{}
+
+	for name, q := range list {
+		result[string(name)] = q.String()
 	}
 
-	_, _ = fmt.Fprintln(opts.Out, string(marshaled))
+	return result
+}
+
+// additionalResourceNames returns the sorted names of all resources besides cpu and memory used by any of
+// the usages.
+func additionalResourceNames(usage []*calc.ResourceUsage) []v1.ResourceName {
+	names := []v1.ResourceName{}
+
+	for _, u := range usage {
+		for _, name := range u.RolloutResources.ResourceNames() {
+			if name != v1.ResourceCPU && name != v1.ResourceMemory && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// printResourceQuota prints a ResourceQuota per group. Multiple quotas are printed as separate yaml documents
+// or as a ResourceQuotaList in json.
+func (opts *KuotaCalcOpts) printResourceQuota(groups []*namespaceGroup) error {
+	list := &v1.ResourceQuotaList{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       "ResourceQuotaList",
+		},
+	}
+
+	for _, group := range groups {
+		namespace := opts.resourceQuotaNamespace
+		if opts.grouped() && group.namespace != "" {
+			namespace = group.namespace
+		} else if namespace == "" {
+			namespace = opts.namespace
+		}
+
+		hard := calc.QuotaUsage(opts.maxRollouts, group.objects, group.usage)
+		list.Items = append(list.Items, *calc.ResourceQuota(opts.resourceQuotaName, namespace, hard))
+	}
+
+	if opts.json {
+		printer := &printers.JSONPrinter{}
+
+		var err error
+
+		if len(list.Items) == 1 {
+			err = printer.PrintObj(&list.Items[0], opts.Out)
+		} else {
+			err = printer.PrintObj(list, opts.Out)
+		}
+
+		if err != nil {
+			return fmt.Errorf("printing resource quota: %w", err)
+		}
+
+		return nil
+	}
+
+	printer := &printers.YAMLPrinter{}
+
+	for i := range list.Items {
+		if err := printer.PrintObj(&list.Items[i], opts.Out); err != nil {
+			return fmt.Errorf("printing resource quota: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// hpaOutput returns the usage at the min and max replicas of a workload scaled by a HorizontalPodAutoscaler, or nil.
+func hpaOutput(u *calc.ResourceUsage) *jsonHpa {
+	if u.HpaMin == nil || u.HpaMax == nil {
+		return nil
+	}
+
+	hpaUsage := func(u *calc.ResourceUsage) jsonHpaUsage {
+		return jsonHpaUsage{
+			Replicas:    u.Details.Replicas,
+			MaxReplicas: u.Details.MaxReplicas,
+			Requests:    quantityStrings(u.RolloutResources.Requests),
+			Limits:      quantityStrings(u.RolloutResources.Limits),
+		}
+	}
+
+	return &jsonHpa{Min: hpaUsage(u.HpaMin), Max: hpaUsage(u.HpaMax)}
+}
+
+// hpaColumns returns the replicas, cpu and memory of a workload scaled by a HorizontalPodAutoscaler at its min
+// and max replicas side by side, or "-" for workloads without HorizontalPodAutoscaler.
+func hpaColumns(u *calc.ResourceUsage) string {
+	if u.HpaMin == nil || u.HpaMax == nil {
+		return "-\t-\t-\t-\t-\t"
+	}
+
+	return fmt.Sprintf("%d/%d\t%s/%s\t%s/%s\t%s/%s\t%s/%s\t",
+		u.HpaMin.Details.Replicas, u.HpaMax.Details.Replicas,
+		u.HpaMin.RolloutResources.Requests.Cpu().String(), u.HpaMax.RolloutResources.Requests.Cpu().String(),
+		u.HpaMin.RolloutResources.Limits.Cpu().String(), u.HpaMax.RolloutResources.Limits.Cpu().String(),
+		u.HpaMin.RolloutResources.Requests.Memory().String(), u.HpaMax.RolloutResources.Requests.Memory().String(),
+		u.HpaMin.RolloutResources.Limits.Memory().String(), u.HpaMax.RolloutResources.Limits.Memory().String(),
+	)
+}
+
+// usesPodLevelResources reports whether the pod resources of the usage are taken from the pod-level resources.
+func usesPodLevelResources(u *calc.ResourceUsage) bool {
+	return u.Details.ResourcesFrom != "" && u.Details.ResourcesFrom != "containers"
 }
 
-func (opts *KuotaCalcOpts) printDetailed(usage []*calc.ResourceUsage) {
+func (opts *KuotaCalcOpts) printDetailed(objects []calc.ResourceObject, usage []*calc.ResourceUsage) {
 	w := tabwriter.NewWriter(opts.Out, 0, 0, 4, ' ', tabwriter.TabIndent)
+	additionalNames := additionalResourceNames(usage)
+	hasStorage := len(calc.TotalStorage(usage)) > 0
+	hasPodLevelResources := slices.ContainsFunc(usage, usesPodLevelResources)
+	hasHpa := slices.ContainsFunc(usage, func(u *calc.ResourceUsage) bool { return u.HpaMin != nil })
+
+	_, _ = fmt.Fprintf(w, "Version\tKind\tName\tReplicas\tStrategy\tMaxReplicas\tCPURequest\tCPULimit\tMemoryRequest\tMemoryLimit\tIsHPA\t")
 
-	_, _ = fmt.Fprintf(w, "Version\tKind\tName\tReplicas\tStrategy\tMaxReplicas\tCPURequest\tCPULimit\tMemoryRequest\tMemoryLimit\tIsHPA\t\n")
+	for _, name := range additionalNames {
+		_, _ = fmt.Fprintf(w, "%s Request\t%s Limit\t", name, name)
+	}
+
+	if hasStorage {
+		_, _ = fmt.Fprintf(w, "StorageRequest\tPVCs\t")
+	}
+
+	if hasHpa {
+		_, _ = fmt.Fprintf(w, "HPAReplicas\tHPACPURequest\tHPACPULimit\tHPAMemoryRequest\tHPAMemoryLimit\t")
+	}
+
+	if hasPodLevelResources {
+		_, _ = fmt.Fprintf(w, "ResourcesFrom\t")
+	}
+
+	if len(opts.filenames) > 0 {
+		_, _ = fmt.Fprintf(w, "Source\t")
+	}
+
+	_, _ = fmt.Fprintln(w)
 
 	for _, u := range usage {
 		isHpa := "false"
@@ -246,19 +861,47 @@ func (opts *KuotaCalcOpts) printDetailed(usage []*calc.ResourceUsage) {
 			isHpa = "true"
 		}
 
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t\n",
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t",
 			u.Details.Version,
 			u.Details.Kind,
 			u.Details.Name,
 			u.Details.Replicas,
 			u.Details.Strategy,
 			u.Details.MaxReplicas,
-			u.RolloutResources.CPUMin.String(),
-			u.RolloutResources.CPUMax.String(),
-			u.RolloutResources.MemoryMin.String(),
-			u.RolloutResources.MemoryMax.String(),
+			u.RolloutResources.Requests.Cpu().String(),
+			u.RolloutResources.Limits.Cpu().String(),
+			u.RolloutResources.Requests.Memory().String(),
+			u.RolloutResources.Limits.Memory().String(),
 			isHpa,
 		)
+
+		for _, name := range additionalNames {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t",
+				u.RolloutResources.Requests.Name(name, resource.DecimalSI).String(),
+				u.RolloutResources.Limits.Name(name, resource.DecimalSI).String(),
+			)
+		}
+
+		if hasStorage {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t",
+				u.Storage.Name(v1.ResourceRequestsStorage, resource.BinarySI).String(),
+				u.Storage.Name(v1.ResourcePersistentVolumeClaims, resource.DecimalSI).String(),
+			)
+		}
+
+		if hasHpa {
+			_, _ = fmt.Fprint(w, hpaColumns(u))
+		}
+
+		if hasPodLevelResources {
+			_, _ = fmt.Fprintf(w, "%s\t", u.Details.ResourcesFrom)
+		}
+
+		if len(opts.filenames) > 0 {
+			_, _ = fmt.Fprintf(w, "%s\t", u.Details.Source)
+		}
+
+		_, _ = fmt.Fprintln(w)
 	}
 
 	if err := w.Flush(); err != nil {
@@ -275,15 +918,69 @@ func (opts *KuotaCalcOpts) printDetailed(usage []*calc.ResourceUsage) {
 	_, _ = fmt.Fprintf(opts.Out, "\nTotal\n")
 
 	opts.printSummary(usage)
+	opts.printObjectCounts(objects, usage)
+}
+
+func (opts *KuotaCalcOpts) printObjectCounts(objects []calc.ResourceObject, usage []*calc.ResourceUsage) {
+	_, _ = fmt.Fprintf(opts.Out, "\nObject Counts\n")
+
+	counts := calc.TotalObjectCount(objects, usage)
+	countNames := []v1.ResourceName{}
+
+	for name := range counts {
+		countNames = append(countNames, name)
+	}
+
+	slices.Sort(countNames)
+
+	for _, name := range countNames {
+		q := counts[name]
+		_, _ = fmt.Fprintf(opts.Out, "%s: %s\n", name, q.String())
+	}
 }
 
 func (opts *KuotaCalcOpts) printSummary(usage []*calc.ResourceUsage) {
 	totalResources := calc.Total(opts.maxRollouts, usage)
 
 	_, _ = fmt.Fprintf(opts.Out, "CPU Request: %s\nCPU Limit: %s\nMemory Request: %s\nMemory Limit: %s\n",
-		totalResources.CPUMin.String(),
-		totalResources.CPUMax.String(),
-		totalResources.MemoryMin.String(),
-		totalResources.MemoryMax.String(),
+		totalResources.Requests.Cpu().String(),
+		totalResources.Limits.Cpu().String(),
+		totalResources.Requests.Memory().String(),
+		totalResources.Limits.Memory().String(),
 	)
+
+	for _, name := range additionalResourceNames(usage) {
+		_, _ = fmt.Fprintf(opts.Out, "%s Request: %s\n%s Limit: %s\n",
+			name,
+			totalResources.Requests.Name(name, resource.DecimalSI).String(),
+			name,
+			totalResources.Limits.Name(name, resource.DecimalSI).String(),
+		)
+	}
+
+	storage := calc.TotalStorage(usage)
+	storageNames := []v1.ResourceName{}
+
+	for name := range storage {
+		storageNames = append(storageNames, name)
+	}
+
+	// the totals come before the per storage class values
+	slices.SortFunc(storageNames, func(a, b v1.ResourceName) int {
+		aPerClass, bPerClass := strings.Contains(string(a), "/"), strings.Contains(string(b), "/")
+		if aPerClass != bPerClass {
+			if aPerClass {
+				return 1
+			}
+
+			return -1
+		}
+
+		return cmp.Compare(a, b)
+	})
+
+	for _, name := range storageNames {
+		q := storage[name]
+		_, _ = fmt.Fprintf(opts.Out, "%s: %s\n", name, q.String())
+	}
 }
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// namespacedPodYAML returns a Pod in the given namespace requesting the given cpu.
+func namespacedPodYAML(name, namespace, cpu string) string {
+	return fmt.Sprintf(`---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  containers:
+  - name: app
+    image: app
+    resources:
+      requests:
+        cpu: %s
+`, name, namespace, cpu)
+}
+
+// quotaYAML returns a ResourceQuota in the given namespace limiting the cpu requests.
+func quotaYAML(name, namespace, cpu string) string {
+	return fmt.Sprintf(`---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  hard:
+    requests.cpu: %s
+`, name, namespace, cpu)
+}
+
+// runKuotaCalc runs the command with the given stdin and arguments and returns its output.
+func runKuotaCalc(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+
+	out := &bytes.Buffer{}
+	streams := genericclioptions.IOStreams{In: strings.NewReader(stdin), Out: out, ErrOut: &bytes.Buffer{}}
+
+	cmd := NewKuotaCalcCmd(&Version{}, streams)
+	cmd.SetArgs(args)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+func TestCheck(t *testing.T) {
+	var tests = []struct {
+		name        string
+		stdin       string
+		quotaFile   string
+		args        []string
+		contains    []string
+		notContains []string
+		exceeded    bool
+		err         string
+	}{
+		{
+			name:     "quota in the input",
+			stdin:    namespacedPodYAML("a", "a", "500m") + quotaYAML("compute", "a", "1"),
+			contains: []string{"ResourceQuota compute", "requests.cpu    1       500m      500m        ok"},
+		},
+		{
+			name:     "exceeded quota in the input",
+			stdin:    namespacedPodYAML("a", "a", "500m") + quotaYAML("compute", "a", "250m"),
+			contains: []string{"requests.cpu    250m    500m      -250m       exceeded"},
+			exceeded: true,
+		},
+		{
+			name:      "exceeded quota file",
+			stdin:     namespacedPodYAML("a", "a", "500m") + quotaYAML("input", "a", "1"),
+			quotaFile: quotaYAML("file", "a", "250m"),
+			contains:  []string{"ResourceQuota input", "ResourceQuota file", "exceeded"},
+			exceeded:  true,
+		},
+		{
+			name:      "quota without namespace applies to every namespace",
+			stdin:     namespacedPodYAML("a", "a", "500m") + namespacedPodYAML("b", "b", "1"),
+			quotaFile: quotaYAML("compute", "", "750m"),
+			args:      []string{"--group-by", "namespace"},
+			contains: []string{
+				"ResourceQuota a/compute", "requests.cpu    750m    500m      250m        ok",
+				"ResourceQuota b/compute", "requests.cpu    750m    1         -250m       exceeded",
+			},
+			exceeded: true,
+		},
+		{
+			name:        "quota of a namespace",
+			stdin:       namespacedPodYAML("a", "a", "500m") + namespacedPodYAML("b", "b", "1"),
+			quotaFile:   quotaYAML("compute", "a", "750m"),
+			args:        []string{"--group-by", "namespace"},
+			contains:    []string{"ResourceQuota a/compute"},
+			notContains: []string{"b/compute", "exceeded"},
+		},
+		{
+			name:  "no quota",
+			stdin: namespacedPodYAML("a", "a", "500m"),
+			err:   "check: no ResourceQuota found in input or quota file",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+
+			args := append([]string{"--check"}, test.args...)
+
+			if test.quotaFile != "" {
+				path := filepath.Join(t.TempDir(), "quota.yaml")
+				r.NoError(os.WriteFile(path, []byte(test.quotaFile), 0o600))
+
+				args = append(args, "--quota-file", path)
+			}
+
+			out, err := runKuotaCalc(t, test.stdin, args...)
+
+			switch {
+			case test.exceeded:
+				r.ErrorIs(err, ErrQuotaExceeded)
+			case test.err != "":
+				r.EqualError(err, test.err)
+			default:
+				r.NoError(err)
+			}
+
+			for _, s := range test.contains {
+				r.Contains(out, s)
+			}
+
+			for _, s := range test.notContains {
+				r.NotContains(out, s)
+			}
+		})
+	}
+}
+
+// namespacedInput contains pods in two namespaces and one without namespace.
+func namespacedInput() string {
+	return namespacedPodYAML("b", "b", "1") + namespacedPodYAML("none", "", "250m") + namespacedPodYAML("a", "a", "500m")
+}
+
+func TestGroupByNamespace(t *testing.T) {
+	r := require.New(t)
+
+	out, err := runKuotaCalc(t, namespacedInput(), "--group-by", "namespace")
+	r.NoError(err)
+
+	// the groups are sorted by namespace, objects without namespace come first
+	none := strings.Index(out, "Namespace: <none>\n\nCPU Request: 250m\n")
+	a := strings.Index(out, "Namespace: a\n\nCPU Request: 500m\n")
+	b := strings.Index(out, "Namespace: b\n\nCPU Request: 1\n")
+
+	r.GreaterOrEqual(none, 0, out)
+	r.Greater(a, none, out)
+	r.Greater(b, a, out)
+}
+
+func TestGroupByNamespaceJSON(t *testing.T) {
+	r := require.New(t)
+
+	out, err := runKuotaCalc(t, namespacedInput(), "--group-by", "namespace", "--json")
+	r.NoError(err)
+
+	var output jsonGroupedOutput
+	r.NoError(json.Unmarshal([]byte(out), &output))
+
+	namespaces := []string{}
+	cpuRequests := []string{}
+
+	for _, namespace := range output.Namespaces {
+		namespaces = append(namespaces, namespace.Namespace)
+		cpuRequests = append(cpuRequests, namespace.Total.CPURequest)
+
+		r.Len(namespace.Resources, 1)
+		r.Equal(namespace.Namespace, namespace.Resources[0].Namespace)
+	}
+
+	r.Equal([]string{"", "a", "b"}, namespaces)
+	r.Equal([]string{"250m", "500m", "1"}, cpuRequests)
+}
+
+func TestGroupByNamespaceResourceQuota(t *testing.T) {
+	r := require.New(t)
+
+	args := []string{"--group-by", "namespace", "--resource-quota", "--resource-quota-namespace", "default"}
+
+	out, err := runKuotaCalc(t, namespacedInput(), append(args, "--json")...)
+	r.NoError(err)
+
+	var list v1.ResourceQuotaList
+	r.NoError(json.Unmarshal([]byte(out), &list))
+
+	namespaces := []string{}
+	cpuRequests := []string{}
+
+	for _, quota := range list.Items {
+		namespaces = append(namespaces, quota.Namespace)
+		cpuRequests = append(cpuRequests, quota.Spec.Hard.Name(v1.ResourceRequestsCPU, resource.DecimalSI).String())
+	}
+
+	// objects without namespace get the quota of --resource-quota-namespace
+	r.Equal([]string{"default", "a", "b"}, namespaces)
+	r.Equal([]string{"250m", "500m", "1"}, cpuRequests)
+
+	// one yaml document per namespace
+	out, err = runKuotaCalc(t, namespacedInput(), args...)
+	r.NoError(err)
+	r.Equal(3, strings.Count(out, "kind: ResourceQuota\n"))
+	r.Contains(out, "  namespace: a\n")
+}
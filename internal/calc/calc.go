@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	v2 "k8s.io/api/autoscaling/v2"
@@ -15,8 +17,11 @@ import (
 	batchV1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/kubernetes/scheme"
 )
 
@@ -46,69 +51,128 @@ func (cErr CalculationError) Unwrap() error {
 	return cErr.err
 }
 
-// ResourceObject is a struct that contains a k8s object, its kind and version and an optional linked object.
+// ResourceObject is a struct that contains a k8s object, its kind and version, an optional linked object
+// and the source (e.g. file name) it was read from. HpaMode selects the replicas of an object scaled by a linked
+// HorizontalPodAutoscaler. LinkedWorkload is the Deployment referenced by the workloadRef of an Argo Rollout.
+// Nodes are the nodes of the cluster a DaemonSet is scheduled to, NodeCount is used if no Nodes are known.
+// JobDuration is the known runtime of the jobs of a CronJob, used to estimate overlapping jobs. QueueProxy are the
+// resources of the queue-proxy sidecar of Knative Services configured for the cluster, see QueueProxyResources.
 type ResourceObject struct {
-	Object       runtime.Object
-	Kind         string
-	Version      string
-	LinkedObject runtime.Object
+	Object         runtime.Object
+	Kind           string
+	Version        string
+	LinkedObject   runtime.Object
+	LinkedWorkload runtime.Object
+	Source         string
+	HpaMode        HpaMode
+	Nodes          []*v1.Node
+	NodeCount      int32
+	JobDuration    time.Duration
+	QueueProxy     *v1.ResourceRequirements
 }
 
 // ResourceUsage summarizes the usage of compute resources for a k8s resource.
+// Storage contains the persistent storage quota usage (e.g. requests.storage, persistentvolumeclaims), which is not
+// affected by rollouts.
+// HpaMin and HpaMax are the usages at the min and max replicas of a linked HorizontalPodAutoscaler, they are
+// only set for objects scaled by one.
 type ResourceUsage struct {
 	NormalResources  Resources
 	RolloutResources Resources
+	Storage          v1.ResourceList
 	Details          Details
+	HpaMin           *ResourceUsage
+	HpaMax           *ResourceUsage
 }
 
 // Details contains a few details of a k8s resource, which are needed to generate a detailed resource
-// usage report.
+// usage report. ResourcesFrom tells whether the pod resources are taken from the containers or the pod-level
+// resources, see PodResources.ResourcesFrom.
 type Details struct {
-	Version     string
-	Kind        string
-	Name        string
-	Strategy    string
-	Replicas    int32
-	MaxReplicas int32
-	Hpa         bool
+	Version       string
+	Kind          string
+	Name          string
+	Namespace     string
+	Source        string
+	ResourcesFrom string
+	Strategy      string
+	Replicas      int32
+	MaxReplicas   int32
+	Hpa           bool
 }
 
-// Resources contains the limits and requests for cpu and memory that are typically used in kubernetes and openshift.
+// Resources contains the requests and limits of all resources (cpu, memory, ephemeral-storage, hugepages,
+// extended resources, ...) that are typically used in kubernetes and openshift.
 // Can be used to apply arithmetic operations equally on all quantities.
 type Resources struct {
-	CPUMin    resource.Quantity
-	CPUMax    resource.Quantity
-	MemoryMin resource.Quantity
-	MemoryMax resource.Quantity
+	Requests v1.ResourceList
+	Limits   v1.ResourceList
 }
 
-// PodResources contain the sum of the resources required by the initContainer, the normal containers
-// and the maximum the pod can require at any time for each resource quantity.
-// In other words, max(Containers.MinCPU, InitContainers.MinCPU), max(Containers.MaxCPU, InitContainers.MaxCPU), etc.
+// PodResources contain the resources required by the running containers (including sidecars), the effective
+// resources required while the init containers run and the maximum the pod can require at any time for each
+// resource quantity.
+// In other words, max(Containers.Requests.cpu, InitContainers.Requests.cpu), max(Containers.Limits.cpu, InitContainers.Limits.cpu), etc.
+// PodLevel contains the names of the resources set by the pod-level resources, which replace the container values.
 type PodResources struct {
 	Containers     Resources
 	InitContainers Resources
 	MaxResources   Resources
+	PodLevel       []v1.ResourceName
+}
+
+// ResourcesFrom describes where the resources of the pod are taken from: "containers", "pod" if all resources
+// are set on pod level or e.g. "pod (cpu), containers" if only some are.
+func (r *PodResources) ResourcesFrom() string {
+	if len(r.PodLevel) == 0 {
+		return "containers"
+	}
+
+	for _, name := range r.MaxResources.ResourceNames() {
+		if !slices.Contains(r.PodLevel, name) {
+			names := make([]string, 0, len(r.PodLevel))
+			for _, podLevelName := range r.PodLevel {
+				names = append(names, string(podLevelName))
+			}
+
+			return fmt.Sprintf("pod (%s), containers", strings.Join(names, ", "))
+		}
+	}
+
+	return "pod"
 }
 
 // ConvertToResources converts a kubernetes/openshift ResourceRequirements struct to a Resources struct
 func ConvertToResources(req *v1.ResourceRequirements) Resources {
 	return Resources{
-		CPUMin:    *req.Requests.Cpu(),
-		CPUMax:    *req.Limits.Cpu(),
-		MemoryMin: *req.Requests.Memory(),
-		MemoryMax: *req.Limits.Memory(),
+		Requests: addResourceList(nil, req.Requests),
+		Limits:   addResourceList(nil, req.Limits),
 	}
 }
 
+// ResourceNames returns the sorted names of all resources that have a request or a limit.
+func (r Resources) ResourceNames() []v1.ResourceName {
+	names := []v1.ResourceName{}
+
+	for _, list := range []v1.ResourceList{r.Requests, r.Limits} {
+		for name := range list {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
 // Add adds the provided y resources to the current value.
 func (r Resources) Add(y Resources) Resources {
-	r.CPUMin.Add(y.CPUMin)
-	r.CPUMax.Add(y.CPUMax)
-	r.MemoryMin.Add(y.MemoryMin)
-	r.MemoryMax.Add(y.MemoryMax)
-
-	return r
+	return Resources{
+		Requests: addResourceList(r.Requests, y.Requests),
+		Limits:   addResourceList(r.Limits, y.Limits),
+	}
 }
 
 // MulInt32 multiplies all resource values by the given multiplier.
@@ -118,44 +182,159 @@ func (r Resources) MulInt32(y int32) Resources {
 
 // Mul multiplies all resource values by the given multiplier.
 func (r Resources) Mul(y float64) Resources {
-	// TODO check if overflow issues due to milli instead of value are to be expected
-	r.CPUMin.SetMilli(int64(float64(r.CPUMin.MilliValue()) * y))
-	r.CPUMax.SetMilli(int64(float64(r.CPUMax.MilliValue()) * y))
-	r.MemoryMin.SetMilli(int64(float64(r.MemoryMin.MilliValue()) * y))
-	r.MemoryMax.SetMilli(int64(float64(r.MemoryMax.MilliValue()) * y))
+	return Resources{
+		Requests: mulResourceList(r.Requests, y),
+		Limits:   mulResourceList(r.Limits, y),
+	}
+}
+
+// addResourceList returns a new list with the sum of both lists for each resource name.
+func addResourceList(a, b v1.ResourceList) v1.ResourceList {
+	sum := v1.ResourceList{}
+
+	for name, q := range a {
+		sum[name] = q.DeepCopy()
+	}
+
+	for name, q := range b {
+		s := sum[name]
+		s.Add(q)
+		sum[name] = s
+	}
+
+	return sum
+}
+
+// mulResourceList returns a new list with every quantity multiplied by y.
+func mulResourceList(list v1.ResourceList, y float64) v1.ResourceList {
+	product := v1.ResourceList{}
+
+	for name, q := range list {
+		// TODO check if overflow issues due to milli instead of value are to be expected
+		p := q.DeepCopy()
+		p.SetMilli(int64(float64(q.MilliValue()) * y))
+		product[name] = p
+	}
+
+	return product
+}
 
-	return r
+// maxResourceList returns a new list with the higher quantity of both lists for each resource name.
+func maxResourceList(a, b v1.ResourceList) v1.ResourceList {
+	result := addResourceList(nil, a)
+
+	for name, q := range b {
+		result[name] = maxQuantity(result[name], q.DeepCopy())
+	}
+
+	return result
 }
 
+// calcPodResources calculates the resources of a pod like the scheduler does (see PodRequests and PodLimits of
+// k8s.io/component-helpers/resource). Sidecars, init containers with restartPolicy Always, keep running next to
+// the containers and are added to them. Every other init container runs alone, but next to the sidecars started
+// before it, the highest of these init steps is the effective init container usage.
+// The pod-level resources replace the values of the containers, the pod overhead is added to the requests and
+// to the limits which are set.
 func calcPodResources(podSpec *v1.PodSpec) (r *PodResources) {
 	r = new(PodResources)
 
 	for i := range podSpec.Containers {
-		container := podSpec.Containers[i]
-
-		r.Containers.CPUMin.Add(*container.Resources.Requests.Cpu())
-		r.Containers.CPUMax.Add(*container.Resources.Limits.Cpu())
-		r.Containers.MemoryMin.Add(*container.Resources.Requests.Memory())
-		r.Containers.MemoryMax.Add(*container.Resources.Limits.Memory())
+		r.Containers = r.Containers.Add(ConvertToResources(&podSpec.Containers[i].Resources))
 	}
 
+	sidecars := Resources{}
+
 	for i := range podSpec.InitContainers {
-		container := podSpec.InitContainers[i]
+		initContainer := &podSpec.InitContainers[i]
+		resources := ConvertToResources(&initContainer.Resources)
 
-		r.InitContainers.CPUMin.Add(*container.Resources.Requests.Cpu())
-		r.InitContainers.CPUMax.Add(*container.Resources.Limits.Cpu())
-		r.InitContainers.MemoryMin.Add(*container.Resources.Requests.Memory())
-		r.InitContainers.MemoryMax.Add(*container.Resources.Limits.Memory())
+		if isSidecar(initContainer) {
+			r.Containers = r.Containers.Add(resources)
+			sidecars = sidecars.Add(resources)
+			resources = sidecars
+		} else {
+			resources = resources.Add(sidecars)
+		}
+
+		r.InitContainers = Resources{
+			Requests: maxResourceList(r.InitContainers.Requests, resources.Requests),
+			Limits:   maxResourceList(r.InitContainers.Limits, resources.Limits),
+		}
 	}
 
-	r.MaxResources.CPUMin = maxQuantity(r.Containers.CPUMin, r.InitContainers.CPUMin)
-	r.MaxResources.CPUMax = maxQuantity(r.Containers.CPUMax, r.InitContainers.CPUMax)
-	r.MaxResources.MemoryMin = maxQuantity(r.Containers.MemoryMin, r.InitContainers.MemoryMin)
-	r.MaxResources.MemoryMax = maxQuantity(r.Containers.MemoryMax, r.InitContainers.MemoryMax)
+	r.applyPodLevelResources(podSpec.Resources)
+
+	r.Containers = addOverhead(r.Containers, podSpec.Overhead)
+	r.InitContainers = addOverhead(r.InitContainers, podSpec.Overhead)
+
+	r.MaxResources = Resources{
+		Requests: maxResourceList(r.Containers.Requests, r.InitContainers.Requests),
+		Limits:   maxResourceList(r.Containers.Limits, r.InitContainers.Limits),
+	}
 
 	return
 }
 
+// podLevelResourceNames are the resources which can be set by the pod-level resources.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var podLevelResourceNames = []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory}
+
+// applyPodLevelResources replaces the container and init container values of every resource set by the pod-level
+// resources. A missing pod-level request defaults to the pod-level limit, as the API server does.
+func (r *PodResources) applyPodLevelResources(requirements *v1.ResourceRequirements) {
+	if requirements == nil {
+		return
+	}
+
+	for _, name := range podLevelResourceNames {
+		limit, hasLimit := requirements.Limits[name]
+
+		request, hasRequest := requirements.Requests[name]
+		if !hasRequest && hasLimit {
+			request, hasRequest = limit, true
+		}
+
+		if !hasRequest {
+			continue
+		}
+
+		for _, resources := range []*Resources{&r.Containers, &r.InitContainers} {
+			resources.Requests = addResourceList(resources.Requests, nil)
+			resources.Requests[name] = request.DeepCopy()
+
+			if hasLimit {
+				resources.Limits = addResourceList(resources.Limits, nil)
+				resources.Limits[name] = limit.DeepCopy()
+			}
+		}
+
+		r.PodLevel = append(r.PodLevel, name)
+	}
+}
+
+// addOverhead adds the pod overhead to the requests and to the limits which are set.
+func addOverhead(r Resources, overhead v1.ResourceList) Resources {
+	limits := v1.ResourceList{}
+
+	for name, q := range overhead {
+		if _, ok := r.Limits[name]; ok {
+			limits[name] = q
+		}
+	}
+
+	return Resources{
+		Requests: addResourceList(r.Requests, overhead),
+		Limits:   addResourceList(r.Limits, limits),
+	}
+}
+
+// isSidecar reports whether the init container is a native sidecar container.
+func isSidecar(container *v1.Container) bool {
+	return container.RestartPolicy != nil && *container.RestartPolicy == v1.ContainerRestartPolicyAlways
+}
+
 func maxQuantity(q1, q2 resource.Quantity) resource.Quantity {
 	if q1.MilliValue() > q2.MilliValue() {
 		return q1
@@ -175,79 +354,74 @@ func diffQuantities(higher, lower *resource.Quantity) resource.Quantity {
 // Total calculates the sum of all usages. maxRollout limits how many simultaneous rollouts are assumed.
 // Negative maxRollout value -> unlimited rollouts.
 func Total(maxRollout int, usage []*ResourceUsage) Resources {
-	var (
-		cpuMinUsage    resource.Quantity
-		cpuMaxUsage    resource.Quantity
-		memoryMinUsage resource.Quantity
-		memoryMaxUsage resource.Quantity
-	)
+	total := Resources{Requests: v1.ResourceList{}, Limits: v1.ResourceList{}}
 
 	if maxRollout <= -1 {
 		// unlimited simultaneous rollout, just sum all rollout resources
 		for _, u := range usage {
-			cpuMinUsage.Add(u.RolloutResources.CPUMin)
-			cpuMaxUsage.Add(u.RolloutResources.CPUMax)
-			memoryMinUsage.Add(u.RolloutResources.MemoryMin)
-			memoryMaxUsage.Add(u.RolloutResources.MemoryMax)
-		}
-	} else {
-		// limited simultaneous rollout
-		// first sum the normal resources
-		// then search for the highest diffs between normal and rollout and add the top `opts.maxRollout` to the sums.
-		for _, u := range usage {
-			cpuMinUsage.Add(u.NormalResources.CPUMin)
-			cpuMaxUsage.Add(u.NormalResources.CPUMax)
-			memoryMinUsage.Add(u.NormalResources.MemoryMin)
-			memoryMaxUsage.Add(u.NormalResources.MemoryMax)
+			total = total.Add(u.RolloutResources)
 		}
 
-		var cpuMinDiffs, cpuMaxDiffs, memoryMinDiffs, memoryMaxDiffs []resource.Quantity
+		return total
+	}
 
-		for _, u := range usage {
-			cpuMinDiffs = append(cpuMinDiffs, diffQuantities(&u.RolloutResources.CPUMin, &u.NormalResources.CPUMin))
+	// limited simultaneous rollout
+	// first sum the normal resources
+	// then search for the highest diffs between normal and rollout and add the top `opts.maxRollout` to the sums.
+	for _, u := range usage {
+		total = total.Add(u.NormalResources)
+	}
 
-			cpuMaxDiffs = append(cpuMaxDiffs, diffQuantities(&u.RolloutResources.CPUMax, &u.NormalResources.CPUMax))
+	return Resources{
+		Requests: addRolloutDiffs(total.Requests, maxRollout, usage, func(r Resources) v1.ResourceList { return r.Requests }),
+		Limits:   addRolloutDiffs(total.Limits, maxRollout, usage, func(r Resources) v1.ResourceList { return r.Limits }),
+	}
+}
 
-			memoryMinDiffs = append(memoryMinDiffs, diffQuantities(&u.RolloutResources.MemoryMin, &u.NormalResources.MemoryMin))
+// addRolloutDiffs adds the maxRollout highest diffs between rollout and normal resources of each resource name to the sums.
+// resourceList selects either the requests or the limits of the usages.
+func addRolloutDiffs(sums v1.ResourceList, maxRollout int, usage []*ResourceUsage, resourceList func(Resources) v1.ResourceList) v1.ResourceList {
+	diffs := map[v1.ResourceName][]resource.Quantity{}
 
-			memoryMaxDiffs = append(memoryMaxDiffs, diffQuantities(&u.RolloutResources.MemoryMax, &u.NormalResources.MemoryMax))
-		}
+	for _, u := range usage {
+		normal := resourceList(u.NormalResources)
 
-		compareQuantityDescending := func(a, b resource.Quantity) int {
-			return a.Cmp(b) * -1
+		for name, rollout := range resourceList(u.RolloutResources) {
+			normalQuantity := normal[name]
+			diffs[name] = append(diffs[name], diffQuantities(&rollout, &normalQuantity))
 		}
+	}
 
-		slices.SortFunc(cpuMinDiffs, compareQuantityDescending)
-		slices.SortFunc(cpuMaxDiffs, compareQuantityDescending)
-		slices.SortFunc(memoryMinDiffs, compareQuantityDescending)
-		slices.SortFunc(memoryMaxDiffs, compareQuantityDescending)
-
-		for i := 0; i < len(cpuMinDiffs) && i < maxRollout; i++ {
-			cpuMinUsage.Add(cpuMinDiffs[i])
-		}
+	compareQuantityDescending := func(a, b resource.Quantity) int {
+		return a.Cmp(b) * -1
+	}
 
-		for i := 0; i < len(cpuMaxDiffs) && i < maxRollout; i++ {
-			cpuMaxUsage.Add(cpuMaxDiffs[i])
-		}
+	for name, nameDiffs := range diffs {
+		slices.SortFunc(nameDiffs, compareQuantityDescending)
 
-		for i := 0; i < len(memoryMinDiffs) && i < maxRollout; i++ {
-			memoryMinUsage.Add(memoryMinDiffs[i])
+		sum := sums[name]
+		for i := 0; i < len(nameDiffs) && i < maxRollout; i++ {
+			sum.Add(nameDiffs[i])
 		}
 
-		for i := 0; i < len(memoryMaxDiffs) && i < maxRollout; i++ {
-			memoryMaxUsage.Add(memoryMaxDiffs[i])
-		}
+		sums[name] = sum
 	}
 
-	return Resources{
-		CPUMin:    cpuMinUsage,
-		CPUMax:    cpuMaxUsage,
-		MemoryMin: memoryMinUsage,
-		MemoryMax: memoryMaxUsage,
-	}
+	return sums
+}
+
+// unstructuredKinds are the supported custom resources, which are decoded as unstructured objects.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var unstructuredKinds = []schema.GroupKind{
+	scaledObjectGroupKind,
+	scaledJobGroupKind,
+	rolloutGroupKind,
+	knativeServiceGroupKind,
 }
 
-// ConvertToRuntimeObjectFromYaml decodes a yaml document into a k8s object. If the kind is not found, it will display a warning.
+// ConvertToRuntimeObjectFromYaml decodes a yaml document into a k8s object. If the kind is not found, it will display a warning
+// and return the document as unstructured object.
 func ConvertToRuntimeObjectFromYaml(yamlData []byte, suppressWarningForUnregisteredKind bool) (object runtime.Object, kind, version *string, err error) {
 	combinedScheme := runtime.NewScheme()
 	_ = scheme.AddToScheme(combinedScheme)
@@ -258,18 +432,30 @@ func ConvertToRuntimeObjectFromYaml(yamlData []byte, suppressWarningForUnregiste
 	object, gvk, err := decoder.Decode(yamlData, nil, nil)
 
 	if err != nil {
-		// when the kind is not found, I just warn and skip
+		// when the kind is not found, I just warn and keep it as unstructured object.
+		// Supported custom resources are kept without a warning.
 		if runtime.IsNotRegisteredError(err) {
-			if !suppressWarningForUnregisteredKind {
-				log.Warn().Msg(err.Error())
+			notRegisteredErr := err
+
+			jsonData, err := yaml.ToJSON(yamlData)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("decoding yaml data: %w", err)
 			}
 
-			unknown := runtime.Unknown{Raw: yamlData}
+			unstructuredObject := &unstructured.Unstructured{}
+
+			_, gvk1, err := unstructured.UnstructuredJSONScheme.Decode(jsonData, nil, unstructuredObject)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("decoding yaml data: %w", err)
+			}
 
-			if _, gvk1, err := decoder.Decode(yamlData, nil, &unknown); err == nil {
-				kind = &gvk1.Kind
-				version = &gvk1.Version
+			if !suppressWarningForUnregisteredKind && !slices.Contains(unstructuredKinds, gvk1.GroupKind()) {
+				log.Warn().Msg(notRegisteredErr.Error())
 			}
+
+			object = unstructuredObject
+			kind = &gvk1.Kind
+			version = &gvk1.Version
 		} else {
 			return nil, nil, nil, fmt.Errorf("decoding yaml data: %w", err)
 		}
@@ -288,59 +474,114 @@ func ConvertToRuntimeObjectFromYaml(yamlData []byte, suppressWarningForUnregiste
 // * apps/v1 - Deployment
 // * apps/v1 - StatefulSet
 // * apps/v1 - DaemonSet
+// * apps/v1 - ReplicaSet
 // * batch/v1 - CronJob
 // * batch/v1 - Job
+// * v1 - PersistentVolumeClaim
 // * v1 - Pod
+// * v1 - ReplicationController
+// * keda.sh/v1alpha1 - ScaledJob
+// * argoproj.io/v1alpha1 - Rollout
+// * serving.knative.dev/v1 - Service
+//
+// For objects scaled by a HorizontalPodAutoscaler, the usages at its min and max replicas are calculated as well.
 func ResourceQuotaFromYaml(resourceObject ResourceObject) (*ResourceUsage, error) {
-	switch obj := resourceObject.Object.(type) {
-	case *openshiftAppsV1.DeploymentConfig:
-		usage, err := deploymentConfig(*obj)
-		if err != nil {
-			return nil, CalculationError{
-				Version: resourceObject.Version,
-				Kind:    resourceObject.Kind,
-				err:     err,
-			}
-		}
+	usage, err := calculate(resourceObject)
+	if err != nil || !usage.Details.Hpa {
+		return usage, err
+	}
 
-		return usage, nil
-	case *appsv1.Deployment:
-		hpa, _ := resourceObject.LinkedObject.(*v2.HorizontalPodAutoscaler)
+	resourceObject.HpaMode = HpaModeMin
 
-		usage, err := deployment(*obj, hpa)
-		if err != nil {
-			return nil, CalculationError{
-				Version: resourceObject.Version,
-				Kind:    resourceObject.Kind,
-				err:     err,
-			}
-		}
+	if usage.HpaMin, err = calculate(resourceObject); err != nil {
+		return nil, err
+	}
 
-		return usage, nil
-	case *appsv1.StatefulSet:
-		usage, err := statefulSet(*obj)
-		if err != nil {
-			return nil, CalculationError{
-				Version: resourceObject.Version,
-				Kind:    resourceObject.Kind,
-				err:     err,
-			}
+	resourceObject.HpaMode = HpaModeMax
+
+	if usage.HpaMax, err = calculate(resourceObject); err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// calculate performs a type assertion on the object and calculates the resource needs of it. Errors are wrapped
+// in a CalculationError.
+func calculate(resourceObject ResourceObject) (*ResourceUsage, error) {
+	usage, err := calculateObject(resourceObject)
+	if err != nil {
+		return nil, CalculationError{
+			Version: resourceObject.Version,
+			Kind:    resourceObject.Kind,
+			err:     err,
 		}
+	}
+
+	return usage, nil
+}
+
+func calculateObject(resourceObject ResourceObject) (*ResourceUsage, error) {
+	hpa, _ := resourceObject.LinkedObject.(*v2.HorizontalPodAutoscaler)
 
-		return usage, nil
+	switch obj := resourceObject.Object.(type) {
+	case *openshiftAppsV1.DeploymentConfig:
+		return deploymentConfig(*obj, hpa, resourceObject.HpaMode)
+	case *appsv1.Deployment:
+		return deployment(*obj, hpa, resourceObject.HpaMode)
+	case *appsv1.StatefulSet:
+		return statefulSet(*obj, hpa, resourceObject.HpaMode)
+	case *appsv1.ReplicaSet:
+		return replicaSet(*obj, hpa, resourceObject.HpaMode), nil
+	case *v1.ReplicationController:
+		return replicationController(*obj, hpa, resourceObject.HpaMode), nil
 	case *appsv1.DaemonSet:
-		return daemonSet(*obj), nil
+		return daemonSet(*obj, resourceObject.Nodes, resourceObject.NodeCount)
 	case *batchV1.Job:
 		return job(*obj), nil
 	case *batchV1.CronJob:
-		return cronjob(*obj), nil
+		return cronjob(*obj, resourceObject.JobDuration)
 	case *v1.Pod:
 		return pod(*obj), nil
+	case *v1.PersistentVolumeClaim:
+		return persistentVolumeClaim(*obj), nil
+	case *unstructured.Unstructured:
+		return calculateUnstructured(resourceObject, obj)
 	default:
-		return nil, CalculationError{
-			Version: resourceObject.Version,
-			Kind:    resourceObject.Kind,
-			err:     ErrResourceNotSupported,
+		return nil, ErrResourceNotSupported
+	}
+}
+
+// isCalculated reports whether calculateObject supports the object, e.g. to tell whether the pods of an owned object
+// are already calculated with its owner.
+func isCalculated(obj runtime.Object) bool {
+	switch obj := obj.(type) {
+	case *openshiftAppsV1.DeploymentConfig, *appsv1.Deployment, *appsv1.StatefulSet, *appsv1.ReplicaSet,
+		*v1.ReplicationController, *appsv1.DaemonSet, *batchV1.Job, *batchV1.CronJob, *v1.Pod, *v1.PersistentVolumeClaim:
+		return true
+	case *unstructured.Unstructured:
+		switch obj.GroupVersionKind().GroupKind() {
+		case scaledJobGroupKind, rolloutGroupKind, knativeServiceGroupKind:
+			return true
 		}
 	}
+
+	return false
+}
+
+// calculateUnstructured calculates the resource needs of the supported custom resources, which are not registered
+// in the scheme and therefore decoded as unstructured objects.
+func calculateUnstructured(resourceObject ResourceObject, obj *unstructured.Unstructured) (*ResourceUsage, error) {
+	switch obj.GroupVersionKind().GroupKind() {
+	case scaledJobGroupKind:
+		return scaledJob(obj)
+	case rolloutGroupKind:
+		hpa, _ := resourceObject.LinkedObject.(*v2.HorizontalPodAutoscaler)
+
+		return rollout(obj, resourceObject.LinkedWorkload, hpa, resourceObject.HpaMode)
+	case knativeServiceGroupKind:
+		return knativeService(obj, resourceObject.HpaMode, resourceObject.QueueProxy)
+	default:
+		return nil, ErrResourceNotSupported
+	}
 }
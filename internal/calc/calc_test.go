@@ -4,6 +4,8 @@ import (
 	"errors"
 	"testing"
 
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/stretchr/testify/require"
@@ -465,6 +467,276 @@ spec:
             memory: 2Gi
       terminationGracePeriodSeconds: 30`
 
+var percentStatefulSet = `
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: percent
+spec:
+  replicas: 10
+  updateStrategy:
+    type: RollingUpdate
+    rollingUpdate:
+      maxUnavailable: 25%
+  selector:
+    matchLabels:
+      app: percent
+  serviceName: percent
+  template:
+    metadata:
+      labels:
+        app: percent
+    spec:
+      initContainers:
+      - image: percent-init
+        name: init
+        resources:
+          limits:
+            cpu: "2"
+            memory: 8Gi
+          requests:
+            cpu: "1"
+            memory: 4Gi
+      containers:
+      - image: percent
+        name: percent
+        resources:
+          limits:
+            cpu: "1"
+            memory: 4Gi
+          requests:
+            cpu: 250m
+            memory: 2Gi`
+
+var partitionStatefulSet = `
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: partition
+spec:
+  replicas: 4
+  updateStrategy:
+    type: RollingUpdate
+    rollingUpdate:
+      maxUnavailable: 2
+      partition: 3
+  selector:
+    matchLabels:
+      app: partition
+  serviceName: partition
+  template:
+    metadata:
+      labels:
+        app: partition
+    spec:
+      initContainers:
+      - image: partition-init
+        name: init
+        resources:
+          limits:
+            cpu: "2"
+            memory: 8Gi
+          requests:
+            cpu: "1"
+            memory: 4Gi
+      containers:
+      - image: partition
+        name: partition
+        resources:
+          limits:
+            cpu: "1"
+            memory: 4Gi
+          requests:
+            cpu: 250m
+            memory: 2Gi`
+
+var parallelStatefulSet = `
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: parallel
+spec:
+  replicas: 3
+  podManagementPolicy: Parallel
+  selector:
+    matchLabels:
+      app: parallel
+  serviceName: parallel
+  template:
+    metadata:
+      labels:
+        app: parallel
+    spec:
+      initContainers:
+      - image: parallel-init
+        name: init
+        resources:
+          limits:
+            cpu: "2"
+            memory: 8Gi
+          requests:
+            cpu: "1"
+            memory: 4Gi
+      containers:
+      - image: parallel
+        name: parallel
+        resources:
+          limits:
+            cpu: "1"
+            memory: 4Gi
+          requests:
+            cpu: 250m
+            memory: 2Gi`
+
+var volumeClaimTemplatesStatefulSet = `
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  labels:
+    app: myapp
+  name: myapp
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      app: myapp
+  serviceName: myapp
+  template:
+    metadata:
+      labels:
+        app: myapp
+    spec:
+      containers:
+      - image: myapp
+        name: myapp
+        resources:
+          requests:
+            cpu: 250m
+            memory: 2Gi
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+    spec:
+      accessModes: ["ReadWriteOnce"]
+      storageClassName: gold
+      resources:
+        requests:
+          storage: 10Gi
+  - metadata:
+      name: logs
+    spec:
+      accessModes: ["ReadWriteOnce"]
+      resources:
+        requests:
+          storage: 1Gi`
+
+var statefulSetClaim = `
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data-myapp-0
+spec:
+  accessModes: ["ReadWriteOnce"]
+  resources:
+    requests:
+      storage: 10Gi`
+
+var statefulSetLogsClaim = `
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: logs-myapp-1
+spec:
+  accessModes: ["ReadWriteOnce"]
+  resources:
+    requests:
+      storage: 10Gi`
+
+var backupClaim = `
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data-myapp-backup
+spec:
+  accessModes: ["ReadWriteOnce"]
+  resources:
+    requests:
+      storage: 10Gi`
+
+var otherNamespaceStatefulSetClaim = `
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data-myapp-0
+  namespace: other
+spec:
+  accessModes: ["ReadWriteOnce"]
+  resources:
+    requests:
+      storage: 10Gi`
+
+var statefulSetPod = `
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: myapp-0
+  ownerReferences:
+    - apiVersion: apps/v1
+      kind: StatefulSet
+      name: myapp
+      controller: true
+spec:
+  containers:
+    - name: myapp
+      image: myapp
+      resources:
+        requests:
+          cpu: 250m
+          memory: 2Gi`
+
+var secondStatefulSetPod = `
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: myapp-1
+  ownerReferences:
+    - apiVersion: apps/v1
+      kind: StatefulSet
+      name: myapp
+      controller: true
+spec:
+  containers:
+    - name: myapp
+      image: myapp
+      resources:
+        requests:
+          cpu: 250m
+          memory: 2Gi`
+
+var normalPersistentVolumeClaim = `
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: myclaim
+spec:
+  accessModes:
+    - ReadWriteMany
+  storageClassName: silver
+  resources:
+    requests:
+      storage: 100Gi`
+
 var service = `
 ---
 apiVersion: v1
@@ -482,6 +754,41 @@ spec:
   sessionAffinity: None
   type: ClusterIP`
 
+var loadBalancerService = `
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: mylb
+spec:
+  ports:
+  - name: http
+    port: 80
+  - name: https
+    port: 443
+  selector:
+    app: myapp
+  type: LoadBalancer`
+
+var normalConfigMap = `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: myconfig
+data:
+  key: value`
+
+var normalScaledObject = `
+---
+apiVersion: keda.sh/v1alpha1
+kind: ScaledObject
+metadata:
+  name: myscaledobject
+spec:
+  scaleTargetRef:
+    name: myapp`
+
 var normalJob = `
 ---
 apiVersion: batch/v1
@@ -504,6 +811,81 @@ spec:
       restartPolicy: Never
   backoffLimit: 4`
 
+var parallelJob = `
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: parallel
+spec:
+  parallelism: 20
+  completions: 5
+  template:
+    spec:
+      containers:
+        - name: worker
+          image: alpine
+          resources:
+            limits:
+              cpu: "1"
+              memory: 4Gi
+            requests:
+              cpu: 250m
+              memory: 2Gi
+      restartPolicy: Never`
+
+var indexedJob = `
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: indexed
+spec:
+  completionMode: Indexed
+  completions: 10
+  parallelism: 3
+  backoffLimitPerIndex: 0
+  template:
+    spec:
+      containers:
+        - name: worker
+          image: alpine
+          resources:
+            limits:
+              cpu: "1"
+              memory: 4Gi
+            requests:
+              cpu: 250m
+              memory: 2Gi
+      restartPolicy: Never`
+
+var podFailurePolicyJob = `
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: pod-failure-policy
+spec:
+  parallelism: 4
+  podFailurePolicy:
+    rules:
+      - action: Ignore
+        onPodConditions:
+          - type: DisruptionTarget
+  template:
+    spec:
+      containers:
+        - name: worker
+          image: alpine
+          resources:
+            limits:
+              cpu: "1"
+              memory: 4Gi
+            requests:
+              cpu: 250m
+              memory: 2Gi
+      restartPolicy: Never`
+
 var normalCronJob = `---
 apiVersion: batch/v1
 kind: CronJob
@@ -682,6 +1064,43 @@ spec:
         memory: 2Gi
   terminationGracePeriodSeconds: 30`
 
+var extendedResourcesPod = `
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  labels:
+    app: mypod
+  name: mypod
+spec:
+  initContainers:
+  - image: mypod
+    imagePullPolicy: Always
+    name: init
+    resources:
+      limits:
+        ephemeral-storage: 4Gi
+      requests:
+        ephemeral-storage: 2Gi
+  containers:
+  - image: mypod
+    imagePullPolicy: Always
+    name: myapp
+    resources:
+      limits:
+        cpu: "1"
+        memory: 4Gi
+        ephemeral-storage: 1Gi
+        hugepages-2Mi: 100Mi
+        nvidia.com/gpu: 1
+      requests:
+        cpu: 250m
+        memory: 2Gi
+        ephemeral-storage: 500Mi
+        hugepages-2Mi: 100Mi
+        nvidia.com/gpu: 1
+  terminationGracePeriodSeconds: 30`
+
 var normalDaemonSet = `
 apiVersion: apps/v1
 kind: DaemonSet
@@ -715,7 +1134,7 @@ func TestResourceQuotaFromYaml(t *testing.T) {
 
 	resourceObject, kind, version, _ := ConvertToRuntimeObjectFromYaml([]byte(service), false)
 
-	usage, err := ResourceQuotaFromYaml(ResourceObject{resourceObject, *kind, *version, nil})
+	usage, err := ResourceQuotaFromYaml(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version})
 	r.Error(err)
 	r.True(errors.Is(err, ErrResourceNotSupported))
 	r.Nil(usage)
@@ -727,7 +1146,7 @@ func TestResourceQuotaFromYaml(t *testing.T) {
 
 	resourceObject, kind, version, _ = ConvertToRuntimeObjectFromYaml([]byte(unsupportedOpenshiftRoute), false)
 
-	usage, err = ResourceQuotaFromYaml(ResourceObject{resourceObject, *kind, *version, nil})
+	usage, err = ResourceQuotaFromYaml(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version})
 	t.Log(err)
 	r.Error(err)
 	r.True(errors.Is(err, ErrResourceNotSupported))
@@ -735,6 +1154,21 @@ func TestResourceQuotaFromYaml(t *testing.T) {
 	r.True(errors.As(err, &calcErr))
 }
 
+// decodeObjects decodes the yaml documents to ResourceObjects, typed if the kind is registered and unstructured
+// otherwise.
+func decodeObjects(r *require.Assertions, documents ...string) []ResourceObject {
+	objects := []ResourceObject{}
+
+	for _, document := range documents {
+		resourceObject, kind, version, err := ConvertToRuntimeObjectFromYaml([]byte(document), false)
+		r.NoError(err)
+
+		objects = append(objects, ResourceObject{Object: resourceObject, Kind: *kind, Version: *version})
+	}
+
+	return objects
+}
+
 func AssertEqualQuantities(r *require.Assertions, expected resource.Quantity, actual resource.Quantity, name string) {
 	r.Conditionf(func() bool { return expected.Equal(actual) }, name+" expected: "+expected.String()+" but was: "+actual.String())
 }
@@ -752,38 +1186,28 @@ func TestTotal(t *testing.T) {
 			usages: []*ResourceUsage{
 				{
 					NormalResources: Resources{
-						CPUMin:    resource.MustParse("100m"),
-						CPUMax:    resource.MustParse("200m"),
-						MemoryMin: resource.MustParse("100Mi"),
-						MemoryMax: resource.MustParse("200Mi"),
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m"), v1.ResourceMemory: resource.MustParse("100Mi")},
+						Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("200m"), v1.ResourceMemory: resource.MustParse("200Mi")},
 					},
 					RolloutResources: Resources{
-						CPUMin:    resource.MustParse("200m"),
-						CPUMax:    resource.MustParse("400m"),
-						MemoryMin: resource.MustParse("200Mi"),
-						MemoryMax: resource.MustParse("400Mi"),
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("200m"), v1.ResourceMemory: resource.MustParse("200Mi")},
+						Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("400m"), v1.ResourceMemory: resource.MustParse("400Mi")},
 					},
 				},
 				{
 					NormalResources: Resources{
-						CPUMin:    resource.MustParse("50m"),
-						CPUMax:    resource.MustParse("100m"),
-						MemoryMin: resource.MustParse("50Mi"),
-						MemoryMax: resource.MustParse("100Mi"),
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("50m"), v1.ResourceMemory: resource.MustParse("50Mi")},
+						Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m"), v1.ResourceMemory: resource.MustParse("100Mi")},
 					},
 					RolloutResources: Resources{
-						CPUMin:    resource.MustParse("100m"),
-						CPUMax:    resource.MustParse("200m"),
-						MemoryMin: resource.MustParse("100Mi"),
-						MemoryMax: resource.MustParse("200Mi"),
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m"), v1.ResourceMemory: resource.MustParse("100Mi")},
+						Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("200m"), v1.ResourceMemory: resource.MustParse("200Mi")},
 					},
 				},
 			},
 			expectedResources: Resources{
-				CPUMin:    resource.MustParse("300m"),
-				CPUMax:    resource.MustParse("600m"),
-				MemoryMin: resource.MustParse("300Mi"),
-				MemoryMax: resource.MustParse("600Mi"),
+				Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("300m"), v1.ResourceMemory: resource.MustParse("300Mi")},
+				Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("600m"), v1.ResourceMemory: resource.MustParse("600Mi")},
 			},
 		},
 		{
@@ -792,38 +1216,28 @@ func TestTotal(t *testing.T) {
 			usages: []*ResourceUsage{
 				{
 					NormalResources: Resources{
-						CPUMin:    resource.MustParse("100m"),
-						CPUMax:    resource.MustParse("200m"),
-						MemoryMin: resource.MustParse("100Mi"),
-						MemoryMax: resource.MustParse("200Mi"),
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m"), v1.ResourceMemory: resource.MustParse("100Mi")},
+						Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("200m"), v1.ResourceMemory: resource.MustParse("200Mi")},
 					},
 					RolloutResources: Resources{
-						CPUMin:    resource.MustParse("200m"),
-						CPUMax:    resource.MustParse("400m"),
-						MemoryMin: resource.MustParse("200Mi"),
-						MemoryMax: resource.MustParse("400Mi"),
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("200m"), v1.ResourceMemory: resource.MustParse("200Mi")},
+						Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("400m"), v1.ResourceMemory: resource.MustParse("400Mi")},
 					},
 				},
 				{
 					NormalResources: Resources{
-						CPUMin:    resource.MustParse("50m"),
-						CPUMax:    resource.MustParse("100m"),
-						MemoryMin: resource.MustParse("50Mi"),
-						MemoryMax: resource.MustParse("100Mi"),
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("50m"), v1.ResourceMemory: resource.MustParse("50Mi")},
+						Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m"), v1.ResourceMemory: resource.MustParse("100Mi")},
 					},
 					RolloutResources: Resources{
-						CPUMin:    resource.MustParse("100m"),
-						CPUMax:    resource.MustParse("200m"),
-						MemoryMin: resource.MustParse("100Mi"),
-						MemoryMax: resource.MustParse("200Mi"),
+						Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m"), v1.ResourceMemory: resource.MustParse("100Mi")},
+						Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("200m"), v1.ResourceMemory: resource.MustParse("200Mi")},
 					},
 				},
 			},
 			expectedResources: Resources{
-				CPUMin:    resource.MustParse("250m"),
-				CPUMax:    resource.MustParse("500m"),
-				MemoryMin: resource.MustParse("250Mi"),
-				MemoryMax: resource.MustParse("500Mi"),
+				Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m"), v1.ResourceMemory: resource.MustParse("250Mi")},
+				Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m"), v1.ResourceMemory: resource.MustParse("500Mi")},
 			},
 		},
 	}
@@ -832,10 +1246,1037 @@ func TestTotal(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			r := require.New(t)
 			total := Total(test.maxRollout, test.usages)
-			AssertEqualQuantities(r, test.expectedResources.CPUMin, total.CPUMin, "cpu request value")
-			AssertEqualQuantities(r, test.expectedResources.CPUMax, total.CPUMax, "cpu limit value")
-			AssertEqualQuantities(r, test.expectedResources.MemoryMin, total.MemoryMin, "memory request value")
-			AssertEqualQuantities(r, test.expectedResources.MemoryMax, total.MemoryMax, "memory limit value")
+			AssertEqualQuantities(r, *test.expectedResources.Requests.Cpu(), *total.Requests.Cpu(), "cpu request value")
+			AssertEqualQuantities(r, *test.expectedResources.Limits.Cpu(), *total.Limits.Cpu(), "cpu limit value")
+			AssertEqualQuantities(r, *test.expectedResources.Requests.Memory(), *total.Requests.Memory(), "memory request value")
+			AssertEqualQuantities(r, *test.expectedResources.Limits.Memory(), *total.Limits.Memory(), "memory limit value")
 		})
 	}
 }
+
+func TestDetailsNamespace(t *testing.T) {
+	var tests = []struct {
+		name     string
+		document string
+	}{
+		{name: "deployment config", document: normalDeploymentConfig},
+		{name: "deployment", document: normalDeployment},
+		{name: "statefulset", document: normalStatefulSet},
+		{name: "daemonset", document: normalDaemonSet},
+		{name: "job", document: normalJob},
+		{name: "cronjob", document: normalCronJob},
+		{name: "pod", document: normalPod},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+
+			resourceObject, kind, version, err := ConvertToRuntimeObjectFromYaml([]byte(test.document), false)
+			r.NoError(err)
+
+			accessor, err := meta.Accessor(resourceObject)
+			r.NoError(err)
+			accessor.SetNamespace("team-a")
+
+			usage, err := ResourceQuotaFromYaml(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version})
+			r.NoError(err)
+			r.Equal("team-a", usage.Details.Namespace, *kind)
+		})
+	}
+}
+
+var limitRange = `
+---
+apiVersion: v1
+kind: LimitRange
+metadata:
+  name: limits
+spec:
+  limits:
+    - type: Container
+      default:
+        cpu: 500m
+        memory: 512Mi
+      defaultRequest:
+        cpu: 250m
+        memory: 256Mi
+      max:
+        cpu: "2"
+        memory: 2Gi
+      maxLimitRequestRatio:
+        cpu: "4"
+    - type: Pod
+      max:
+        memory: 3Gi`
+
+var noResourcesPod = `
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: defaults
+spec:
+  initContainers:
+    - name: greedy
+      image: busybox
+      resources:
+        requests:
+          cpu: 100m
+        limits:
+          cpu: "1"
+          memory: 4Gi
+  containers:
+    - name: app
+      image: myapp
+    - name: sidecar
+      image: mysidecar
+      resources:
+        limits:
+          cpu: "1"
+          memory: 1Gi`
+
+var sidecarPod = `
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: sidecars
+spec:
+  initContainers:
+    - name: migrate
+      image: migrate
+      resources:
+        requests:
+          cpu: "2"
+          memory: 1Gi
+        limits:
+          cpu: "2"
+          memory: 1Gi
+    - name: istio-proxy
+      image: istio/proxyv2
+      restartPolicy: Always
+      resources:
+        requests:
+          cpu: 100m
+          memory: 128Mi
+        limits:
+          cpu: 500m
+          memory: 256Mi
+    - name: setup
+      image: setup
+      resources:
+        requests:
+          cpu: 1950m
+          memory: 128Mi
+        limits:
+          cpu: 1950m
+          memory: 128Mi
+  containers:
+    - name: app
+      image: myapp
+      resources:
+        requests:
+          cpu: 500m
+          memory: 256Mi
+        limits:
+          cpu: "1"
+          memory: 512Mi`
+
+var kataRuntimeClass = `
+---
+apiVersion: node.k8s.io/v1
+kind: RuntimeClass
+metadata:
+  name: kata
+handler: kata
+overhead:
+  podFixed:
+    cpu: 250m
+    memory: 160Mi`
+
+var kataPod = `
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: sandboxed
+spec:
+  runtimeClassName: kata
+  containers:
+    - name: app
+      image: myapp
+      resources:
+        requests:
+          cpu: 500m
+          memory: 256Mi
+        limits:
+          cpu: "1"`
+
+var podLevelResourcesPod = `
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod-level
+spec:
+  resources:
+    requests:
+      cpu: "1"
+    limits:
+      cpu: "2"
+      memory: 2Gi
+  containers:
+    - name: app
+      image: myapp
+      resources:
+        requests:
+          cpu: 500m
+          memory: 256Mi
+          ephemeral-storage: 1Gi
+        limits:
+          cpu: "1"
+          memory: 512Mi
+    - name: sidecar
+      image: mysidecar
+      resources:
+        requests:
+          cpu: 250m
+          memory: 128Mi`
+
+var deploymentConfigHpa = `
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: normal
+spec:
+  scaleTargetRef:
+    apiVersion: apps.openshift.io/v1
+    kind: DeploymentConfig
+    name: normal
+  minReplicas: 2
+  maxReplicas: 15`
+
+var statefulSetHpa = `
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: myapp
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: StatefulSet
+    name: myapp
+  maxReplicas: 4`
+
+var missingTargetHpa = `
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: gone
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: gone
+  maxReplicas: 3`
+
+var v1Hpa = `
+---
+apiVersion: autoscaling/v1
+kind: HorizontalPodAutoscaler
+metadata:
+  name: normal
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: normal
+  minReplicas: 2
+  maxReplicas: 12
+  targetCPUUtilizationPercentage: 80`
+
+var v2beta2Hpa = `
+---
+apiVersion: autoscaling/v2beta2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: myapp
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: StatefulSet
+    name: myapp
+  minReplicas: 1
+  maxReplicas: 5
+  metrics:
+    - type: Resource
+      resource:
+        name: cpu
+        target:
+          type: Utilization
+          averageUtilization: 80`
+
+var deploymentScaledObject = `
+---
+apiVersion: keda.sh/v1alpha1
+kind: ScaledObject
+metadata:
+  name: consumer
+spec:
+  scaleTargetRef:
+    name: normal
+  minReplicaCount: 1
+  maxReplicaCount: 20
+  triggers:
+    - type: kafka
+      metadata:
+        topic: orders`
+
+var normalScaledJob = `
+---
+apiVersion: keda.sh/v1alpha1
+kind: ScaledJob
+metadata:
+  name: worker
+spec:
+  maxReplicaCount: 5
+  jobTargetRef:
+    parallelism: 2
+    template:
+      spec:
+        restartPolicy: Never
+        containers:
+          - name: worker
+            image: worker
+            resources:
+              requests:
+                cpu: 100m
+                memory: 64Mi
+              limits:
+                cpu: 200m
+                memory: 128Mi
+  triggers:
+    - type: rabbitmq
+      metadata:
+        queueName: jobs`
+
+var defaultsScaledJob = `
+---
+apiVersion: keda.sh/v1alpha1
+kind: ScaledJob
+metadata:
+  name: defaults
+spec:
+  maxReplicaCount: 2
+  jobTargetRef:
+    template:
+      spec:
+        restartPolicy: Never
+        runtimeClassName: kata
+        containers:
+          - name: worker
+            image: worker
+  triggers:
+    - type: rabbitmq
+      metadata:
+        queueName: jobs`
+
+var blueGreenRollout = `
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: bluegreen
+spec:
+  replicas: 4
+  strategy:
+    blueGreen:
+      activeService: bluegreen-active
+      previewService: bluegreen-preview
+      scaleDownDelaySeconds: 30
+  template:
+    spec:
+      containers:
+        - name: app
+          image: app
+          resources:
+            requests:
+              cpu: 100m
+              memory: 64Mi
+            limits:
+              cpu: 200m
+              memory: 128Mi`
+
+var previewRollout = `
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: preview
+spec:
+  replicas: 4
+  strategy:
+    blueGreen:
+      activeService: preview-active
+      previewService: preview-preview
+      previewReplicaCount: 1
+      scaleDownDelaySeconds: 0
+  template:
+    spec:
+      containers:
+        - name: app
+          image: app
+          resources:
+            requests:
+              cpu: 100m
+              memory: 64Mi
+            limits:
+              cpu: 200m
+              memory: 128Mi`
+
+var delayedPreviewRollout = `
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: delayed-preview
+spec:
+  replicas: 4
+  strategy:
+    blueGreen:
+      activeService: delayed-preview-active
+      previewService: delayed-preview-preview
+      previewReplicaCount: 1
+  template:
+    spec:
+      containers:
+        - name: app
+          image: app
+          resources:
+            requests:
+              cpu: 100m
+              memory: 64Mi
+            limits:
+              cpu: 200m
+              memory: 128Mi`
+
+var canaryRollout = `
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: canary
+spec:
+  replicas: 10
+  strategy:
+    canary:
+      steps:
+        - setWeight: 20
+        - pause: {}
+        - setWeight: 50
+        - pause:
+            duration: 10m
+  template:
+    spec:
+      containers:
+        - name: app
+          image: app
+          resources:
+            requests:
+              cpu: 100m
+              memory: 64Mi
+            limits:
+              cpu: 200m
+              memory: 128Mi`
+
+var trafficRoutingRollout = `
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: traffic
+spec:
+  replicas: 10
+  strategy:
+    canary:
+      dynamicStableScale: true
+      trafficRouting:
+        nginx:
+          stableIngress: traffic
+      steps:
+        - setCanaryScale:
+            replicas: 1
+        - setWeight: 20
+        - pause: {}
+        - setCanaryScale:
+            matchTrafficWeight: true
+        - setWeight: 50
+        - pause: {}
+  template:
+    spec:
+      containers:
+        - name: app
+          image: app
+          resources:
+            requests:
+              cpu: 100m
+              memory: 64Mi
+            limits:
+              cpu: 200m
+              memory: 128Mi`
+
+var workloadRefRollout = `
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: normal-rollout
+spec:
+  replicas: 2
+  workloadRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: normal
+  strategy:
+    blueGreen:
+      activeService: normal`
+
+var defaultsRollout = `
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: defaults
+spec:
+  replicas: 2
+  strategy:
+    blueGreen:
+      activeService: defaults
+  template:
+    spec:
+      runtimeClassName: kata
+      containers:
+        - name: app
+          image: app`
+
+var defaultsWorkloadRefRollout = `
+---
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: defaults-rollout
+spec:
+  replicas: 2
+  workloadRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: defaults
+  strategy:
+    blueGreen:
+      activeService: defaults`
+
+var defaultsDeployment = `
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: defaults
+spec:
+  replicas: 0
+  selector:
+    matchLabels:
+      app: defaults
+  template:
+    metadata:
+      labels:
+        app: defaults
+    spec:
+      runtimeClassName: kata
+      containers:
+        - name: app
+          image: app`
+
+var normalKnativeService = `
+---
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: hello
+spec:
+  template:
+    metadata:
+      annotations:
+        autoscaling.knative.dev/min-scale: "2"
+        autoscaling.knative.dev/max-scale: "10"
+        queue.sidecar.serving.knative.dev/memory-resource-request: 64Mi
+    spec:
+      containers:
+        - name: hello
+          image: hello
+          resources:
+            requests:
+              cpu: 100m
+              memory: 128Mi
+            limits:
+              cpu: 200m
+              memory: 256Mi
+  traffic:
+    - latestRevision: true
+      percent: 80
+    - revisionName: hello-00001
+      percent: 20
+    - revisionName: hello-00001
+      tag: previous`
+
+var defaultKnativeService = `
+---
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: defaults
+spec:
+  template:
+    spec:
+      containers:
+        - name: hello
+          image: hello
+          resources:
+            requests:
+              cpu: 100m
+              memory: 128Mi`
+
+var noResourcesKnativeService = `
+---
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: no-resources
+spec:
+  template:
+    metadata:
+      annotations:
+        autoscaling.knative.dev/max-scale: "2"
+    spec:
+      runtimeClassName: kata
+      containers:
+        - name: hello
+          image: hello`
+
+var knativeDeploymentConfig = `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config-deployment
+  namespace: knative-serving
+data:
+  queue-sidecar-cpu-request: 100m
+  queue-sidecar-cpu-limit: 500m
+  queue-sidecar-memory-request: 32Mi
+  queue-sidecar-memory-limit: 128Mi`
+
+var invalidKnativeDeploymentConfig = `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config-deployment
+  namespace: knative-serving
+data:
+  queue-sidecar-memory-limit: lots`
+
+var normalReplicaSet = `
+---
+apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: frontend
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      app: frontend
+  template:
+    metadata:
+      labels:
+        app: frontend
+    spec:
+      initContainers:
+        - name: migrate
+          image: migrate
+          resources:
+            requests:
+              cpu: 500m
+              memory: 128Mi
+            limits:
+              cpu: "1"
+              memory: 256Mi
+      containers:
+        - name: php-redis
+          image: php-redis
+          resources:
+            requests:
+              cpu: 100m
+              memory: 64Mi
+            limits:
+              cpu: 200m
+              memory: 128Mi`
+
+var deploymentReplicaSet = `
+---
+apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: normal-5d4f8c7b9
+  ownerReferences:
+    - apiVersion: apps/v1
+      kind: Deployment
+      name: normal
+      controller: true
+spec:
+  replicas: 10
+  selector:
+    matchLabels:
+      app: normal
+  template:
+    spec:
+      containers:
+        - name: normal
+          image: normal`
+
+var normalReplicationController = `
+---
+apiVersion: v1
+kind: ReplicationController
+metadata:
+  name: nginx
+spec:
+  replicas: 2
+  selector:
+    app: nginx
+  template:
+    metadata:
+      labels:
+        app: nginx
+    spec:
+      containers:
+        - name: nginx
+          image: nginx
+          resources:
+            requests:
+              cpu: 250m
+              memory: 128Mi
+            limits:
+              cpu: 500m
+              memory: 256Mi`
+
+var deploymentPod = `
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: normal-5d4f8c7b9-x2x7k
+  ownerReferences:
+    - apiVersion: apps/v1
+      kind: ReplicaSet
+      name: normal-5d4f8c7b9
+      controller: true
+spec:
+  containers:
+    - name: normal
+      image: normal`
+
+var succeededJobPod = `
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pi-8xk2p
+  ownerReferences:
+    - apiVersion: batch/v1
+      kind: Job
+      name: pi
+      controller: true
+spec:
+  containers:
+    - name: pi
+      image: alpine
+status:
+  phase: Succeeded`
+
+var failedJobPod = `
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pi-4m9qz
+  ownerReferences:
+    - apiVersion: batch/v1
+      kind: Job
+      name: pi
+      controller: true
+spec:
+  containers:
+    - name: pi
+      image: alpine
+status:
+  phase: Failed`
+
+var prometheus = `
+---
+apiVersion: monitoring.coreos.com/v1
+kind: Prometheus
+metadata:
+  name: k8s
+  uid: 5f0c3c9e-prometheus
+spec:
+  replicas: 2`
+
+var prometheusStatefulSet = `
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: prometheus-k8s
+  ownerReferences:
+    - apiVersion: monitoring.coreos.com/v1
+      kind: Prometheus
+      name: k8s
+      uid: 5f0c3c9e-prometheus
+      controller: true
+spec:
+  replicas: 2
+  serviceName: prometheus-operated
+  selector:
+    matchLabels:
+      app: prometheus
+  template:
+    metadata:
+      labels:
+        app: prometheus
+    spec:
+      containers:
+        - name: prometheus
+          image: prometheus
+          resources:
+            requests:
+              cpu: 500m
+              memory: 1Gi`
+
+var prometheusPod = `
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: prometheus-k8s-0
+  ownerReferences:
+    - apiVersion: apps/v1
+      kind: StatefulSet
+      name: prometheus-k8s
+      controller: true
+spec:
+  containers:
+    - name: prometheus
+      image: prometheus
+      resources:
+        requests:
+          cpu: 500m
+          memory: 1Gi`
+
+var clusterNodes = []string{`
+---
+apiVersion: v1
+kind: Node
+metadata:
+  name: worker-1
+  labels:
+    role: worker
+    zone: a
+`, `
+---
+apiVersion: v1
+kind: Node
+metadata:
+  name: worker-2
+  labels:
+    role: worker
+    zone: b
+    gpus: "4"
+spec:
+  taints:
+    - key: gpu
+      value: "true"
+      effect: NoSchedule
+`, `
+---
+apiVersion: v1
+kind: Node
+metadata:
+  name: worker-3
+  labels:
+    role: worker
+    zone: c
+spec:
+  unschedulable: true
+  taints:
+    - key: node.kubernetes.io/unschedulable
+      effect: NoSchedule
+    - key: maintenance
+      effect: PreferNoSchedule
+`, `
+---
+apiVersion: v1
+kind: Node
+metadata:
+  name: control-plane-1
+  labels:
+    node-role.kubernetes.io/control-plane: ""
+spec:
+  taints:
+    - key: node-role.kubernetes.io/control-plane
+      effect: NoSchedule
+`}
+
+var surgeDaemonSet = `
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: surge
+spec:
+  selector:
+    matchLabels:
+      name: surge
+  updateStrategy:
+    type: RollingUpdate
+    rollingUpdate:
+      maxSurge: 25%
+      maxUnavailable: 0
+  template:
+    metadata:
+      labels:
+        name: surge
+    spec:
+      containers:
+      - name: surge
+        image: surge
+        resources:
+          limits:
+            memory: 2Gi
+            cpu: "2"
+          requests:
+            cpu: 500m
+            memory: 200Mi`
+
+var onDeleteDaemonSet = `
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: ondelete
+spec:
+  selector:
+    matchLabels:
+      name: ondelete
+  updateStrategy:
+    type: OnDelete
+  template:
+    metadata:
+      labels:
+        name: ondelete
+    spec:
+      containers:
+      - name: ondelete
+        image: ondelete
+        resources:
+          limits:
+            memory: 2Gi
+            cpu: "2"
+          requests:
+            cpu: 500m
+            memory: 200Mi`
+
+var overlappingCronJob = `
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: report
+spec:
+  schedule: "*/10 * * * *"
+  timeZone: Europe/Zurich
+  jobTemplate:
+    spec:
+      parallelism: 2
+      activeDeadlineSeconds: 1500
+      template:
+        spec:
+          containers:
+            - name: report
+              image: report
+              resources:
+                limits:
+                  cpu: "1"
+                  memory: 4Gi
+                requests:
+                  cpu: 250m
+                  memory: 2Gi
+          restartPolicy: OnFailure`
+
+var forbidCronJob = `
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+  annotations:
+    kuota-calc/job-duration: 3h
+spec:
+  schedule: "@hourly"
+  concurrencyPolicy: Forbid
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: backup
+              image: backup
+              resources:
+                limits:
+                  cpu: "1"
+                  memory: 4Gi
+                requests:
+                  cpu: 250m
+                  memory: 2Gi
+          restartPolicy: OnFailure`
+
+var annotatedCronJob = `
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: sync
+  annotations:
+    kuota-calc/job-duration: 20m
+spec:
+  schedule: "0,15,30,45 * * * *"
+  jobTemplate:
+    spec:
+      activeDeadlineSeconds: 3600
+      template:
+        spec:
+          containers:
+            - name: sync
+              image: sync
+              resources:
+                limits:
+                  cpu: "1"
+                  memory: 4Gi
+                requests:
+                  cpu: 250m
+                  memory: 2Gi
+          restartPolicy: OnFailure`
@@ -0,0 +1,71 @@
+package calc
+
+import (
+	"cmp"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// QuotaCheck is the result of comparing a single hard limit of a ResourceQuota with the calculated need.
+type QuotaCheck struct {
+	Name     v1.ResourceName
+	Hard     resource.Quantity
+	Needed   resource.Quantity
+	Headroom resource.Quantity
+	Exceeded bool
+}
+
+// quotaAliases maps the legacy ResourceQuota resource names to the ones returned by QuotaResourceList.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var quotaAliases = map[v1.ResourceName]v1.ResourceName{
+	v1.ResourceCPU:              v1.ResourceRequestsCPU,
+	v1.ResourceMemory:           v1.ResourceRequestsMemory,
+	v1.ResourceEphemeralStorage: v1.ResourceRequestsEphemeralStorage,
+}
+
+// computeQuotaNames are the compute resources kuota-calc always calculates, QuotaResourceList leaves them out if no
+// container needs them.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var computeQuotaNames = []v1.ResourceName{
+	v1.ResourceRequestsCPU,
+	v1.ResourceLimitsCPU,
+	v1.ResourceRequestsMemory,
+	v1.ResourceLimitsMemory,
+}
+
+// CheckQuota compares every hard limit of the quota with the needed resources. Hard limits of resources
+// kuota-calc does not calculate are skipped, cpu and memory missing in the needed resources are needed with zero.
+// The result is sorted by resource name.
+func CheckQuota(quota *v1.ResourceQuota, needed v1.ResourceList) []QuotaCheck {
+	checks := []QuotaCheck{}
+
+	for name, hard := range quota.Spec.Hard {
+		lookup := name
+		if alias, ok := quotaAliases[name]; ok {
+			lookup = alias
+		}
+
+		need, ok := needed[lookup]
+		if !ok && !slices.Contains(computeQuotaNames, lookup) {
+			continue
+		}
+
+		checks = append(checks, QuotaCheck{
+			Name:     name,
+			Hard:     hard.DeepCopy(),
+			Needed:   need.DeepCopy(),
+			Headroom: diffQuantities(&hard, &need),
+			Exceeded: need.Cmp(hard) > 0,
+		})
+	}
+
+	slices.SortFunc(checks, func(a, b QuotaCheck) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return checks
+}
@@ -0,0 +1,63 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var computeResourceQuota = `---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: compute
+spec:
+  hard:
+    cpu: "4"
+    limits.cpu: "6"
+    limits.memory: 40Gi
+    pods: "10"`
+
+func TestCheckQuota(t *testing.T) {
+	r := require.New(t)
+
+	object, _, _, err := ConvertToRuntimeObjectFromYaml([]byte(computeResourceQuota), false)
+	r.NoError(err)
+
+	quota, ok := object.(*v1.ResourceQuota)
+	r.True(ok)
+
+	needed := QuotaResourceList(Resources{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("3250m"), v1.ResourceMemory: resource.MustParse("26Gi")},
+		Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("6500m"), v1.ResourceMemory: resource.MustParse("32Gi")},
+	})
+
+	checks := CheckQuota(quota, needed)
+	r.Len(checks, 3)
+
+	r.Equal(v1.ResourceCPU, checks[0].Name)
+	AssertEqualQuantities(r, resource.MustParse("3250m"), checks[0].Needed, "cpu needed")
+	AssertEqualQuantities(r, resource.MustParse("750m"), checks[0].Headroom, "cpu headroom")
+	r.False(checks[0].Exceeded)
+
+	r.Equal(v1.ResourceLimitsCPU, checks[1].Name)
+	AssertEqualQuantities(r, resource.MustParse("-500m"), checks[1].Headroom, "cpu limit headroom")
+	r.True(checks[1].Exceeded)
+
+	r.Equal(v1.ResourceLimitsMemory, checks[2].Name)
+	AssertEqualQuantities(r, resource.MustParse("8Gi"), checks[2].Headroom, "memory limit headroom")
+	r.False(checks[2].Exceeded)
+
+	// limits are needed with zero if no container sets them
+	checks = CheckQuota(quota, QuotaResourceList(Resources{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("3250m")},
+	}))
+	r.Len(checks, 3)
+
+	r.Equal(v1.ResourceLimitsCPU, checks[1].Name)
+	AssertEqualQuantities(r, resource.MustParse("0"), checks[1].Needed, "cpu limit needed")
+	AssertEqualQuantities(r, resource.MustParse("6"), checks[1].Headroom, "cpu limit headroom")
+	r.False(checks[1].Exceeded)
+}
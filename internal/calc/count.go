@@ -0,0 +1,137 @@
+package calc
+
+import (
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// clusterScopedKinds are well known kinds, which are not namespaced and therefore never count towards a ResourceQuota.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var clusterScopedKinds = []string{
+	"APIService",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"CustomResourceDefinition",
+	"IngressClass",
+	"MutatingWebhookConfiguration",
+	"Namespace",
+	"Node",
+	"PersistentVolume",
+	"PriorityClass",
+	"RuntimeClass",
+	"StorageClass",
+	"ValidatingWebhookConfiguration",
+}
+
+// ObjectCount returns the object count quota usage of a single object, which is count/<resource>.<group> and
+// the legacy names for services (including services.loadbalancers and services.nodeports), configmaps, secrets,
+// replicationcontrollers and resourcequotas.
+// Pods and PersistentVolumeClaims are not counted, as their count depends on the workloads, see TotalObjectCount.
+func ObjectCount(resourceObject ResourceObject) v1.ResourceList {
+	if resourceObject.Object == nil {
+		return v1.ResourceList{}
+	}
+
+	gvk := resourceObject.Object.GetObjectKind().GroupVersionKind()
+	if gvk.Kind == "" || slices.Contains(clusterScopedKinds, gvk.Kind) {
+		return v1.ResourceList{}
+	}
+
+	if gvk.Group == "" && (gvk.Kind == "Pod" || gvk.Kind == "PersistentVolumeClaim") {
+		return v1.ResourceList{}
+	}
+
+	plural, _ := meta.UnsafeGuessKindToResource(gvk)
+	count := v1.ResourceList{
+		objectCountQuotaResourceName(plural.GroupResource()): countQuantity(1),
+	}
+
+	if gvk.Group != "" {
+		return count
+	}
+
+	switch obj := resourceObject.Object.(type) {
+	case *v1.Service:
+		count[v1.ResourceServices] = countQuantity(1)
+
+		if obj.Spec.Type == v1.ServiceTypeLoadBalancer {
+			count[v1.ResourceServicesLoadBalancers] = countQuantity(1)
+		}
+
+		if usesNodePorts(obj) {
+			count[v1.ResourceServicesNodePorts] = countQuantity(int64(len(obj.Spec.Ports)))
+		}
+	case *v1.ConfigMap:
+		count[v1.ResourceConfigMaps] = countQuantity(1)
+	case *v1.Secret:
+		count[v1.ResourceSecrets] = countQuantity(1)
+	case *v1.ReplicationController:
+		count[v1.ResourceReplicationControllers] = countQuantity(1)
+	case *v1.ResourceQuota:
+		count[v1.ResourceQuotas] = countQuantity(1)
+	}
+
+	return count
+}
+
+// usesNodePorts reports whether every port of the service allocates a node port.
+func usesNodePorts(service *v1.Service) bool {
+	switch service.Spec.Type {
+	case v1.ServiceTypeNodePort:
+		return true
+	case v1.ServiceTypeLoadBalancer:
+		return service.Spec.AllocateLoadBalancerNodePorts == nil || *service.Spec.AllocateLoadBalancerNodePorts
+	default:
+		return false
+	}
+}
+
+// peakPods returns the max number of pods of a usage during a rollout. Pods have no replicas, but run a single pod.
+func peakPods(u *ResourceUsage) int64 {
+	switch u.Details.Kind {
+	case "PersistentVolumeClaim":
+		return 0
+	case "Pod":
+		return max(1, int64(u.Details.MaxReplicas))
+	default:
+		return int64(u.Details.MaxReplicas)
+	}
+}
+
+// TotalObjectCount calculates the object count quota usage of all objects. Pods are counted with their peak count
+// during rollouts of all usages and PersistentVolumeClaims including the claims of StatefulSets.
+func TotalObjectCount(objects []ResourceObject, usage []*ResourceUsage) v1.ResourceList {
+	total := v1.ResourceList{}
+
+	for _, obj := range objects {
+		total = addResourceList(total, ObjectCount(obj))
+	}
+
+	var pods int64
+	for _, u := range usage {
+		pods += peakPods(u)
+	}
+
+	total[v1.ResourcePods] = countQuantity(pods)
+	total[objectCountQuotaResourceName(v1.Resource("pods"))] = countQuantity(pods)
+
+	if claims, ok := TotalStorage(usage)[v1.ResourcePersistentVolumeClaims]; ok {
+		total[objectCountQuotaResourceName(v1.Resource("persistentvolumeclaims"))] = claims
+	}
+
+	return total
+}
+
+// objectCountQuotaResourceName returns count/<resource>.<group>, or count/<resource> for the core group.
+func objectCountQuotaResourceName(groupResource schema.GroupResource) v1.ResourceName {
+	return v1.ResourceName("count/" + groupResource.String())
+}
+
+func countQuantity(count int64) resource.Quantity {
+	return *resource.NewQuantity(count, resource.DecimalSI)
+}
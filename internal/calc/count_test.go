@@ -0,0 +1,96 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestObjectCount(t *testing.T) {
+	var tests = []struct {
+		name     string
+		document string
+		expected map[v1.ResourceName]string
+	}{
+		{
+			name:     "cluster ip service",
+			document: service,
+			expected: map[v1.ResourceName]string{"count/services": "1", "services": "1"},
+		},
+		{
+			name:     "load balancer service",
+			document: loadBalancerService,
+			expected: map[v1.ResourceName]string{
+				"count/services":         "1",
+				"services":               "1",
+				"services.loadbalancers": "1",
+				"services.nodeports":     "2",
+			},
+		},
+		{
+			name:     "configmap",
+			document: normalConfigMap,
+			expected: map[v1.ResourceName]string{"count/configmaps": "1", "configmaps": "1"},
+		},
+		{
+			name:     "deployment",
+			document: normalDeployment,
+			expected: map[v1.ResourceName]string{"count/deployments.apps": "1"},
+		},
+		{
+			name:     "unregistered kind",
+			document: normalScaledObject,
+			expected: map[v1.ResourceName]string{"count/scaledobjects.keda.sh": "1"},
+		},
+		{
+			name:     "pod",
+			document: normalPod,
+			expected: map[v1.ResourceName]string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+
+			resourceObject, kind, version, err := ConvertToRuntimeObjectFromYaml([]byte(test.document), true)
+			r.NoError(err)
+
+			count := ObjectCount(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version})
+			r.Len(count, len(test.expected))
+
+			for name, value := range test.expected {
+				AssertEqualQuantities(r, resource.MustParse(value), count[name], string(name))
+			}
+		})
+	}
+}
+
+func TestTotalObjectCount(t *testing.T) {
+	r := require.New(t)
+
+	objects := []ResourceObject{}
+	usage := []*ResourceUsage{}
+
+	for _, document := range []string{normalDeployment, volumeClaimTemplatesStatefulSet, normalPod, normalJob, service} {
+		resourceObject, kind, version, _ := ConvertToRuntimeObjectFromYaml([]byte(document), false)
+		object := ResourceObject{Object: resourceObject, Kind: *kind, Version: *version}
+		objects = append(objects, object)
+
+		u, err := ResourceQuotaFromYaml(object)
+		if err == nil {
+			usage = append(usage, u)
+		}
+	}
+
+	total := TotalObjectCount(objects, usage)
+
+	// 13 deployment pods during rollout, 3 statefulset pods, 1 pod and 1 job pod with its replacement
+	AssertEqualQuantities(r, resource.MustParse("19"), total[v1.ResourcePods], "pods")
+	AssertEqualQuantities(r, resource.MustParse("19"), total["count/pods"], "count/pods")
+	AssertEqualQuantities(r, resource.MustParse("6"), total["count/persistentvolumeclaims"], "count/persistentvolumeclaims")
+	AssertEqualQuantities(r, resource.MustParse("1"), total["count/jobs.batch"], "count/jobs.batch")
+	AssertEqualQuantities(r, resource.MustParse("1"), total[v1.ResourceServices], "services")
+}
@@ -1,23 +1,134 @@
 package calc
 
-import batchV1 "k8s.io/api/batch/v1"
+import (
+	"fmt"
+	"math"
+	"time"
+	_ "time/tzdata" // the time zones of CronJobs are available without a time zone database on the system
 
-func cronjob(cronjob batchV1.CronJob) *ResourceUsage {
+	"github.com/robfig/cron/v3"
+	batchV1 "k8s.io/api/batch/v1"
+)
+
+const (
+	// jobDurationAnnotation sets the known runtime of the jobs of a CronJob, e.g. 45m.
+	jobDurationAnnotation = "kuota-calc/job-duration"
+	// cronJobSampleRuns limits the runs of a schedule searched for the shortest interval between two runs.
+	cronJobSampleRuns = 10000
+)
+
+// cronjob calculates the resources of the jobs of a CronJob running at the same time. With the Allow
+// concurrencyPolicy, a new job can start before the previous ones have finished. The number of overlapping jobs is
+// estimated from the job runtime and the shortest interval of the schedule. Every job runs its pods like a Job.
+func cronjob(cronjob batchV1.CronJob, jobDuration time.Duration) (*ResourceUsage, error) {
+	jobs, err := concurrentJobs(&cronjob, jobDuration)
+	if err != nil {
+		return nil, fmt.Errorf("cronjob: %s: %w", cronjob.Name, err)
+	}
+
+	policy := cronjob.Spec.ConcurrencyPolicy
+	if policy == "" {
+		policy = batchV1.AllowConcurrent
+	}
+
+	pods, replacements := jobReplicas(&cronjob.Spec.JobTemplate.Spec)
+	pods, replacements = jobs*pods, jobs*replacements
 	podResources := calcPodResources(&cronjob.Spec.JobTemplate.Spec.Template.Spec)
 
 	resourceUsage := ResourceUsage{
 		// TODO should jobs always be considered with their rollout resources?
-		NormalResources:  podResources.Containers,
-		RolloutResources: podResources.MaxResources,
+		NormalResources:  podResources.Containers.MulInt32(pods),
+		RolloutResources: podResources.MaxResources.MulInt32(pods).Add(podResources.Containers.MulInt32(replacements)),
 		Details: Details{
-			Version:     cronjob.APIVersion,
-			Kind:        cronjob.Kind,
-			Name:        cronjob.Name,
-			Strategy:    "",
-			Replicas:    0,
-			MaxReplicas: 0,
+			Version:       cronjob.APIVersion,
+			Kind:          cronjob.Kind,
+			Name:          cronjob.Name,
+			Namespace:     cronjob.Namespace,
+			ResourcesFrom: podResources.ResourcesFrom(),
+			Strategy:      string(policy),
+			Replicas:      pods,
+			MaxReplicas:   pods + replacements,
 		},
 	}
 
-	return &resourceUsage
+	return &resourceUsage, nil
+}
+
+// concurrentJobs estimates the max number of jobs of a CronJob running at the same time. Forbid and Replace never
+// run more than one job. With Allow, the jobs overlap if their runtime exceeds the interval between two runs.
+// The runtime is taken from the job-duration annotation, the given jobDuration or the activeDeadlineSeconds of
+// the job template, in this order. If the runtime is unknown, the jobs are assumed not to overlap.
+func concurrentJobs(cronjob *batchV1.CronJob, jobDuration time.Duration) (int32, error) {
+	if cronjob.Spec.ConcurrencyPolicy == batchV1.ForbidConcurrent || cronjob.Spec.ConcurrencyPolicy == batchV1.ReplaceConcurrent {
+		return 1, nil
+	}
+
+	if value, ok := cronjob.Annotations[jobDurationAnnotation]; ok {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid annotation %s: %w", jobDurationAnnotation, err)
+		}
+
+		jobDuration = duration
+	}
+
+	if deadline := cronjob.Spec.JobTemplate.Spec.ActiveDeadlineSeconds; jobDuration == 0 && deadline != nil {
+		jobDuration = time.Duration(*deadline) * time.Second
+	}
+
+	if jobDuration <= 0 {
+		return 1, nil
+	}
+
+	interval, err := shortestInterval(cronjob.Spec.Schedule, cronjob.Spec.TimeZone)
+	if err != nil {
+		return 0, err
+	}
+
+	jobs := math.Ceil(float64(jobDuration) / float64(interval))
+
+	return int32(min(jobs, math.MaxInt32)), nil
+}
+
+// shortestInterval returns the shortest interval between two runs of a cron schedule in the given time zone
+// (UTC by default) within a year, including changes from and to daylight saving time. Schedules running less than
+// twice a year return a year.
+func shortestInterval(schedule string, timeZone *string) (time.Duration, error) {
+	location := time.UTC
+
+	if timeZone != nil {
+		var err error
+
+		location, err = time.LoadLocation(*timeZone)
+		if err != nil {
+			return 0, fmt.Errorf("invalid timeZone %q: %w", *timeZone, err)
+		}
+	}
+
+	parsed, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schedule %q: %w", schedule, err)
+	}
+
+	// a fixed start makes the result independent of the current date
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, location)
+	end := start.AddDate(1, 0, 0)
+	shortest := end.Sub(start)
+
+	previous := parsed.Next(start)
+	if previous.IsZero() {
+		return shortest, nil
+	}
+
+	for range cronJobSampleRuns {
+		next := parsed.Next(previous)
+		if next.IsZero() || next.After(end) {
+			break
+		}
+
+		shortest = min(shortest, next.Sub(previous))
+		previous = next
+	}
+
+	return shortest, nil
 }
@@ -2,6 +2,7 @@ package calc
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -11,6 +12,7 @@ func TestCronJob(t *testing.T) {
 	var tests = []struct {
 		name        string
 		cronjob     string
+		jobDuration time.Duration
 		cpuMin      resource.Quantity
 		cpuMax      resource.Quantity
 		memoryMin   resource.Quantity
@@ -20,12 +22,61 @@ func TestCronJob(t *testing.T) {
 		strategy    string
 	}{
 		{
-			name:      "ok",
-			cronjob:   normalCronJob,
-			cpuMin:    resource.MustParse("250m"),
-			cpuMax:    resource.MustParse("1"),
-			memoryMin: resource.MustParse("2Gi"),
-			memoryMax: resource.MustParse("4Gi"),
+			name:        "ok",
+			cronjob:     normalCronJob,
+			cpuMin:      resource.MustParse("500m"),
+			cpuMax:      resource.MustParse("2"),
+			memoryMin:   resource.MustParse("4Gi"),
+			memoryMax:   resource.MustParse("8Gi"),
+			replicas:    1,
+			maxReplicas: 2,
+			strategy:    "Allow",
+		},
+		{
+			name:        "overlapping jobs",
+			cronjob:     overlappingCronJob,
+			cpuMin:      resource.MustParse("3"),
+			cpuMax:      resource.MustParse("12"),
+			memoryMin:   resource.MustParse("24Gi"),
+			memoryMax:   resource.MustParse("48Gi"),
+			replicas:    6,
+			maxReplicas: 12,
+			strategy:    "Allow",
+		},
+		{
+			name:        "job duration",
+			cronjob:     normalCronJob,
+			jobDuration: 150 * time.Second,
+			cpuMin:      resource.MustParse("1500m"),
+			cpuMax:      resource.MustParse("6"),
+			memoryMin:   resource.MustParse("12Gi"),
+			memoryMax:   resource.MustParse("24Gi"),
+			replicas:    3,
+			maxReplicas: 6,
+			strategy:    "Allow",
+		},
+		{
+			name:        "job duration annotation",
+			cronjob:     annotatedCronJob,
+			jobDuration: time.Hour,
+			cpuMin:      resource.MustParse("1"),
+			cpuMax:      resource.MustParse("4"),
+			memoryMin:   resource.MustParse("8Gi"),
+			memoryMax:   resource.MustParse("16Gi"),
+			replicas:    2,
+			maxReplicas: 4,
+			strategy:    "Allow",
+		},
+		{
+			name:        "forbid",
+			cronjob:     forbidCronJob,
+			cpuMin:      resource.MustParse("500m"),
+			cpuMax:      resource.MustParse("2"),
+			memoryMin:   resource.MustParse("4Gi"),
+			memoryMax:   resource.MustParse("8Gi"),
+			replicas:    1,
+			maxReplicas: 2,
+			strategy:    "Forbid",
 		},
 	}
 
@@ -36,14 +87,14 @@ func TestCronJob(t *testing.T) {
 
 				resourceObject, kind, version, _ := ConvertToRuntimeObjectFromYaml([]byte(test.cronjob), false)
 
-				usage, err := ResourceQuotaFromYaml(ResourceObject{resourceObject, *kind, *version, nil})
+				usage, err := ResourceQuotaFromYaml(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version, JobDuration: test.jobDuration})
 				r.NoError(err)
 				r.NotEmpty(usage)
 
-				AssertEqualQuantities(r, test.cpuMin, usage.RolloutResources.CPUMin, "cpu request value")
-				AssertEqualQuantities(r, test.cpuMax, usage.RolloutResources.CPUMax, "cpu limit value")
-				AssertEqualQuantities(r, test.memoryMin, usage.RolloutResources.MemoryMin, "memory request value")
-				AssertEqualQuantities(r, test.memoryMax, usage.RolloutResources.MemoryMax, "memory limit value")
+				AssertEqualQuantities(r, test.cpuMin, *usage.RolloutResources.Requests.Cpu(), "cpu request value")
+				AssertEqualQuantities(r, test.cpuMax, *usage.RolloutResources.Limits.Cpu(), "cpu limit value")
+				AssertEqualQuantities(r, test.memoryMin, *usage.RolloutResources.Requests.Memory(), "memory request value")
+				AssertEqualQuantities(r, test.memoryMax, *usage.RolloutResources.Limits.Memory(), "memory limit value")
 				r.Equalf(test.replicas, usage.Details.Replicas, "replicas")
 				r.Equalf(test.maxReplicas, usage.Details.MaxReplicas, "maxReplicas")
 				r.Equalf(test.strategy, usage.Details.Strategy, "strategy")
@@ -51,3 +102,43 @@ func TestCronJob(t *testing.T) {
 		)
 	}
 }
+
+func TestShortestInterval(t *testing.T) {
+	var tests = []struct {
+		name     string
+		schedule string
+		timeZone string
+		interval time.Duration
+		err      bool
+	}{
+		{name: "every 6 hours", schedule: "0 */6 * * *", interval: 6 * time.Hour},
+		{name: "irregular", schedule: "0 8,12,13 * * 1-5", interval: time.Hour},
+		// the day daylight saving time starts has 23 hours
+		{name: "daylight saving time", schedule: "@daily", timeZone: "America/New_York", interval: 23 * time.Hour},
+		{name: "utc", schedule: "@daily", interval: 24 * time.Hour},
+		{name: "once a year", schedule: "0 0 1 1 *", interval: 365 * 24 * time.Hour},
+		{name: "invalid schedule", schedule: "every minute", err: true},
+		{name: "invalid time zone", schedule: "@daily", timeZone: "Mars/Olympus_Mons", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+
+			var timeZone *string
+			if test.timeZone != "" {
+				timeZone = &test.timeZone
+			}
+
+			interval, err := shortestInterval(test.schedule, timeZone)
+			if test.err {
+				r.Error(err)
+
+				return
+			}
+
+			r.NoError(err)
+			r.Equal(test.interval, interval)
+		})
+	}
+}
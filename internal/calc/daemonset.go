@@ -1,24 +1,97 @@
 package calc
 
 import (
+	"errors"
+	"fmt"
+	"math"
+
 	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-func daemonSet(dSet appsv1.DaemonSet) *ResourceUsage {
+// daemonSet calculates the cpu/memory resources a DaemonSet needs, with one pod on every node it is scheduled to.
+// The update strategy is taken into account.
+func daemonSet(dSet appsv1.DaemonSet, nodes []*v1.Node, nodeCount int32) (*ResourceUsage, error) {
+	var (
+		maxUnavailable int32 // max amount of nodes without an available pod during a rollout
+		maxSurge       int32 // max amount of nodes running the old and the new pod during a rollout
+	)
+
+	replicas := daemonSetNodeCount(&dSet.Spec.Template.Spec, nodes, nodeCount)
+	strategy := dSet.Spec.UpdateStrategy
+
+	// https://kubernetes.io/docs/tasks/manage-daemon/update-daemon-set/#daemonset-update-strategy
+	switch strategy.Type {
+	case appsv1.OnDeleteDaemonSetStrategyType:
+		// OnDelete doesn't do anything until you kill pods, which it then replaces with the newer ones.
+		// The most expensive case would be killing all pods at once, with the init containers being more expensive than the normal container.
+		maxUnavailable = replicas
+	case "":
+		// RollingUpdate is the default and can be an empty string. If so, set the defaults and continue calculation.
+		strategy = appsv1.DaemonSetUpdateStrategy{Type: appsv1.RollingUpdateDaemonSetStrategyType}
+
+		fallthrough
+	case appsv1.RollingUpdateDaemonSetStrategyType:
+		var err error
+
+		maxUnavailable, maxSurge, err = daemonSetRollingUpdate(strategy.RollingUpdate, replicas)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("daemonset: %s update strategy %q is unknown", dSet.Name, strategy.Type)
+	}
+
 	podResources := calcPodResources(&dSet.Spec.Template.Spec)
+	rolloutResources := podResources.Containers.MulInt32(replicas - maxUnavailable).Add(podResources.MaxResources.MulInt32(maxSurge + maxUnavailable))
 
 	resourceUsage := ResourceUsage{
-		NormalResources:  podResources.Containers,
-		RolloutResources: podResources.MaxResources,
+		NormalResources:  podResources.Containers.MulInt32(replicas),
+		RolloutResources: rolloutResources,
 		Details: Details{
-			Version:     dSet.APIVersion,
-			Kind:        dSet.Kind,
-			Name:        dSet.Name,
-			Strategy:    "",
-			Replicas:    1,
-			MaxReplicas: 1,
+			Version:       dSet.APIVersion,
+			Kind:          dSet.Kind,
+			Name:          dSet.Name,
+			Namespace:     dSet.Namespace,
+			ResourcesFrom: podResources.ResourcesFrom(),
+			Strategy:      string(strategy.Type),
+			Replicas:      replicas,
+			MaxReplicas:   replicas + maxSurge,
 		},
 	}
 
-	return &resourceUsage
+	return &resourceUsage, nil
+}
+
+// daemonSetRollingUpdate returns the absolute maxUnavailable and maxSurge of a rolling update, which default to 1
+// and 0. Unlike for Deployments, both are calculated by rounding up.
+func daemonSetRollingUpdate(rollingUpdate *appsv1.RollingUpdateDaemonSet, replicas int32) (maxUnavailable, maxSurge int32, err error) {
+	maxUnavailableValue := intstr.FromInt32(1)
+	maxSurgeValue := intstr.FromInt32(0)
+
+	if rollingUpdate != nil && rollingUpdate.MaxUnavailable != nil {
+		maxUnavailableValue = *rollingUpdate.MaxUnavailable
+	}
+
+	if rollingUpdate != nil && rollingUpdate.MaxSurge != nil {
+		maxSurgeValue = *rollingUpdate.MaxSurge
+	}
+
+	maxUnavailableInt, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailableValue, int(replicas), true)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	maxSurgeInt, err := intstr.GetScaledValueFromIntOrPercent(&maxSurgeValue, int(replicas), true)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if maxUnavailableInt < 0 || maxUnavailableInt > math.MaxInt32 || maxSurgeInt < 0 || maxSurgeInt > math.MaxInt32 {
+		return 0, 0, errors.New("maxUnavailable or maxSurge out of int32 boundaries")
+	}
+
+	// there are no more pods to replace than nodes
+	return min(int32(maxUnavailableInt), replicas), min(int32(maxSurgeInt), replicas), nil
 }
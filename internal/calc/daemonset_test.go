@@ -16,9 +16,10 @@ func TestDaemonSet(t *testing.T) {
 		cpuMax      resource.Quantity
 		memoryMin   resource.Quantity
 		memoryMax   resource.Quantity
+		nodes       int32
 		replicas    int32
 		maxReplicas int32
-		strategy    appsv1.StatefulSetUpdateStrategyType
+		strategy    appsv1.DaemonSetUpdateStrategyType
 	}{
 		{
 			name:        "ok",
@@ -29,6 +30,31 @@ func TestDaemonSet(t *testing.T) {
 			cpuMax:      resource.MustParse("2"),
 			memoryMin:   resource.MustParse("200Mi"),
 			memoryMax:   resource.MustParse("2Gi"),
+			strategy:    appsv1.RollingUpdateDaemonSetStrategyType,
+		},
+		{
+			name:        "surge",
+			daemonset:   surgeDaemonSet,
+			nodes:       10,
+			replicas:    10,
+			maxReplicas: 13,
+			cpuMin:      resource.MustParse("6500m"),
+			cpuMax:      resource.MustParse("26"),
+			memoryMin:   resource.MustParse("2600Mi"),
+			memoryMax:   resource.MustParse("26Gi"),
+			strategy:    appsv1.RollingUpdateDaemonSetStrategyType,
+		},
+		{
+			name:        "on delete",
+			daemonset:   onDeleteDaemonSet,
+			nodes:       4,
+			replicas:    4,
+			maxReplicas: 4,
+			cpuMin:      resource.MustParse("2"),
+			cpuMax:      resource.MustParse("8"),
+			memoryMin:   resource.MustParse("800Mi"),
+			memoryMax:   resource.MustParse("8Gi"),
+			strategy:    appsv1.OnDeleteDaemonSetStrategyType,
 		},
 	}
 
@@ -39,14 +65,14 @@ func TestDaemonSet(t *testing.T) {
 
 				resourceObject, kind, version, _ := ConvertToRuntimeObjectFromYaml([]byte(test.daemonset), false)
 
-				usage, err := ResourceQuotaFromYaml(ResourceObject{resourceObject, *kind, *version, nil})
+				usage, err := ResourceQuotaFromYaml(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version, NodeCount: test.nodes})
 				r.NoError(err)
 				r.NotEmpty(usage)
 
-				AssertEqualQuantities(r, test.cpuMin, usage.RolloutResources.CPUMin, "cpu request value")
-				AssertEqualQuantities(r, test.cpuMax, usage.RolloutResources.CPUMax, "cpu limit value")
-				AssertEqualQuantities(r, test.memoryMin, usage.RolloutResources.MemoryMin, "memory request value")
-				AssertEqualQuantities(r, test.memoryMax, usage.RolloutResources.MemoryMax, "memory limit value")
+				AssertEqualQuantities(r, test.cpuMin, *usage.RolloutResources.Requests.Cpu(), "cpu request value")
+				AssertEqualQuantities(r, test.cpuMax, *usage.RolloutResources.Limits.Cpu(), "cpu limit value")
+				AssertEqualQuantities(r, test.memoryMin, *usage.RolloutResources.Requests.Memory(), "memory request value")
+				AssertEqualQuantities(r, test.memoryMax, *usage.RolloutResources.Limits.Memory(), "memory limit value")
 				r.Equalf(test.replicas, usage.Details.Replicas, "replicas")
 				r.Equalf(test.maxReplicas, usage.Details.MaxReplicas, "maxReplicas")
 				r.Equalf(string(test.strategy), usage.Details.Strategy, "strategy")
@@ -13,7 +13,7 @@ import (
 
 // calculates the cpu/memory resources a single deployment needs. Replicas and the deployment
 // strategy are taken into account.
-func deployment(deployment appsv1.Deployment, hpa *v2.HorizontalPodAutoscaler) (*ResourceUsage, error) { //nolint:funlen // disable function length linting
+func deployment(deployment appsv1.Deployment, hpa *v2.HorizontalPodAutoscaler, hpaMode HpaMode) (*ResourceUsage, error) { //nolint:funlen // disable function length linting
 	var (
 		maxUnavailable      int32 // max amount of unavailable pods during a deployment
 		maxSurge            int32 // max amount of pods that are allowed in addition to replicas during deployment
@@ -22,18 +22,17 @@ func deployment(deployment appsv1.Deployment, hpa *v2.HorizontalPodAutoscaler) (
 		//  but probes haven't succeeded yet
 	)
 
-	replicas := deployment.Spec.Replicas
-
-	isHpa := false
-
-	if hpa != nil {
-		replicas = &hpa.Spec.MaxReplicas
-		isHpa = true
+	// https://github.com/kubernetes/api/blob/v0.18.4/apps/v1/types.go#L310
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
 	}
 
+	replicas, isHpa := hpaReplicas(replicas, hpa, hpaMode)
+
 	strategy := deployment.Spec.Strategy
 
-	if *replicas == 0 {
+	if replicas == 0 {
 		return &ResourceUsage{
 			NormalResources:  Resources{},
 			RolloutResources: Resources{},
@@ -41,9 +40,11 @@ func deployment(deployment appsv1.Deployment, hpa *v2.HorizontalPodAutoscaler) (
 				Version:     deployment.APIVersion,
 				Kind:        deployment.Kind,
 				Name:        deployment.Name,
-				Replicas:    *replicas,
-				MaxReplicas: *replicas,
+				Namespace:   deployment.Namespace,
+				Replicas:    replicas,
+				MaxReplicas: replicas,
 				Strategy:    string(strategy.Type),
+				Hpa:         isHpa,
 			},
 		}, nil
 	}
@@ -51,8 +52,8 @@ func deployment(deployment appsv1.Deployment, hpa *v2.HorizontalPodAutoscaler) (
 	switch strategy.Type {
 	case appsv1.RecreateDeploymentStrategyType:
 		// kill all existing pods, then recreate new ones at once -> no overhead on recreate
-		maxNonReadyPodCount = *replicas
-		maxUnavailable = *replicas
+		maxNonReadyPodCount = replicas
+		maxUnavailable = replicas
 		maxSurge = 0
 	case "":
 		// RollingUpdate is the default and can be an empty string. If so, set the defaults
@@ -85,7 +86,7 @@ func deployment(deployment appsv1.Deployment, hpa *v2.HorizontalPodAutoscaler) (
 		}
 
 		// docs say, that the absolute number is calculated by rounding down.
-		maxUnavailableInt, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailableValue, int(*replicas), false)
+		maxUnavailableInt, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailableValue, int(replicas), false)
 		if err != nil {
 			return nil, err
 		}
@@ -97,7 +98,7 @@ func deployment(deployment appsv1.Deployment, hpa *v2.HorizontalPodAutoscaler) (
 		maxUnavailable = int32(maxUnavailableInt)
 
 		// docs say, absolute number is calculated by rounding up.
-		maxSurgeInt, err := intstr.GetScaledValueFromIntOrPercent(&maxSurgeValue, int(*replicas), true)
+		maxSurgeInt, err := intstr.GetScaledValueFromIntOrPercent(&maxSurgeValue, int(replicas), true)
 		if err != nil {
 			return nil, err
 		}
@@ -115,20 +116,22 @@ func deployment(deployment appsv1.Deployment, hpa *v2.HorizontalPodAutoscaler) (
 	}
 
 	podResources := calcPodResources(&deployment.Spec.Template.Spec)
-	rolloutResources := podResources.Containers.MulInt32(*replicas - maxUnavailable).Add(podResources.MaxResources.MulInt32(maxNonReadyPodCount))
-	normalResources := podResources.Containers.MulInt32(*replicas)
+	rolloutResources := podResources.Containers.MulInt32(replicas - maxUnavailable).Add(podResources.MaxResources.MulInt32(maxNonReadyPodCount))
+	normalResources := podResources.Containers.MulInt32(replicas)
 
 	resourceUsage := ResourceUsage{
 		NormalResources:  normalResources,
 		RolloutResources: rolloutResources,
 		Details: Details{
-			Version:     deployment.APIVersion,
-			Kind:        deployment.Kind,
-			Name:        deployment.Name,
-			Replicas:    *replicas,
-			Strategy:    string(strategy.Type),
-			MaxReplicas: *replicas + maxSurge,
-			Hpa:         isHpa,
+			Version:       deployment.APIVersion,
+			Kind:          deployment.Kind,
+			Name:          deployment.Name,
+			Namespace:     deployment.Namespace,
+			ResourcesFrom: podResources.ResourcesFrom(),
+			Replicas:      replicas,
+			Strategy:      string(strategy.Type),
+			MaxReplicas:   replicas + maxSurge,
+			Hpa:           isHpa,
 		},
 	}
 
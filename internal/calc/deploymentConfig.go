@@ -6,12 +6,13 @@ import (
 	"math"
 
 	openshiftAppsV1 "github.com/openshift/api/apps/v1"
+	v2 "k8s.io/api/autoscaling/v2"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // calculates the cpu/memory resources a single deployment needs. Replicas and the deployment
 // strategy are taken into account.
-func deploymentConfig(deploymentConfig openshiftAppsV1.DeploymentConfig) (*ResourceUsage, error) { //nolint:funlen // disable function length linting
+func deploymentConfig(deploymentConfig openshiftAppsV1.DeploymentConfig, hpa *v2.HorizontalPodAutoscaler, hpaMode HpaMode) (*ResourceUsage, error) { //nolint:funlen // disable function length linting
 	var (
 		maxUnavailable      int32 // max amount of unavailable pods during a deployment
 		maxSurge            int32 // max amount of pods that are allowed in addition to replicas during deployment
@@ -20,7 +21,7 @@ func deploymentConfig(deploymentConfig openshiftAppsV1.DeploymentConfig) (*Resou
 		//  but probes haven't succeeded yet
 	)
 
-	replicas := deploymentConfig.Spec.Replicas
+	replicas, isHpa := hpaReplicas(deploymentConfig.Spec.Replicas, hpa, hpaMode)
 	strategy := deploymentConfig.Spec.Strategy
 
 	if replicas == 0 {
@@ -31,9 +32,11 @@ func deploymentConfig(deploymentConfig openshiftAppsV1.DeploymentConfig) (*Resou
 				Version:     deploymentConfig.APIVersion,
 				Kind:        deploymentConfig.Kind,
 				Name:        deploymentConfig.Name,
+				Namespace:   deploymentConfig.Namespace,
 				Replicas:    replicas,
 				MaxReplicas: replicas,
 				Strategy:    string(strategy.Type),
+				Hpa:         isHpa,
 			},
 		}, nil
 	}
@@ -113,12 +116,15 @@ func deploymentConfig(deploymentConfig openshiftAppsV1.DeploymentConfig) (*Resou
 		NormalResources:  normalResources,
 		RolloutResources: rolloutResources,
 		Details: Details{
-			Version:     deploymentConfig.APIVersion,
-			Kind:        deploymentConfig.Kind,
-			Name:        deploymentConfig.Name,
-			Replicas:    replicas,
-			Strategy:    string(strategy.Type),
-			MaxReplicas: replicas + maxSurge,
+			Version:       deploymentConfig.APIVersion,
+			Kind:          deploymentConfig.Kind,
+			Name:          deploymentConfig.Name,
+			Namespace:     deploymentConfig.Namespace,
+			ResourcesFrom: podResources.ResourcesFrom(),
+			Replicas:      replicas,
+			Strategy:      string(strategy.Type),
+			MaxReplicas:   replicas + maxSurge,
+			Hpa:           isHpa,
 		},
 	}
 
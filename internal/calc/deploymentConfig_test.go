@@ -40,14 +40,14 @@ func TestDeploymentConfig(t *testing.T) {
 
 			resourceObject, kind, version, _ := ConvertToRuntimeObjectFromYaml([]byte(test.deploymentConfig), false)
 
-			usage, err := ResourceQuotaFromYaml(ResourceObject{resourceObject, *kind, *version, nil})
+			usage, err := ResourceQuotaFromYaml(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version})
 			r.NoError(err)
 			r.NotEmpty(usage)
 
-			AssertEqualQuantities(r, test.cpuMin, usage.RolloutResources.CPUMin, "cpu request value")
-			AssertEqualQuantities(r, test.cpuMax, usage.RolloutResources.CPUMax, "cpu limit value")
-			AssertEqualQuantities(r, test.memoryMin, usage.RolloutResources.MemoryMin, "memory request value")
-			AssertEqualQuantities(r, test.memoryMax, usage.RolloutResources.MemoryMax, "memory limit value")
+			AssertEqualQuantities(r, test.cpuMin, *usage.RolloutResources.Requests.Cpu(), "cpu request value")
+			AssertEqualQuantities(r, test.cpuMax, *usage.RolloutResources.Limits.Cpu(), "cpu limit value")
+			AssertEqualQuantities(r, test.memoryMin, *usage.RolloutResources.Requests.Memory(), "memory request value")
+			AssertEqualQuantities(r, test.memoryMax, *usage.RolloutResources.Limits.Memory(), "memory limit value")
 			r.Equal(test.replicas, usage.Details.Replicas, "replicas")
 			r.Equal(string(test.strategy), usage.Details.Strategy, "strategy")
 			r.Equal(test.maxReplicas, usage.Details.MaxReplicas, "maxReplicas")
@@ -0,0 +1,152 @@
+package calc
+
+import (
+	"fmt"
+
+	autoscalingV1 "k8s.io/api/autoscaling/v1"
+	v2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// HpaMode selects the replicas used for objects scaled by a HorizontalPodAutoscaler.
+type HpaMode string
+
+const (
+	// HpaModeMax uses the maxReplicas of the HorizontalPodAutoscaler, this is the default.
+	HpaModeMax HpaMode = "max"
+	// HpaModeMin uses the minReplicas of the HorizontalPodAutoscaler.
+	HpaModeMin HpaMode = "min"
+	// HpaModeSpec uses the replicas of the scaled object.
+	HpaModeSpec HpaMode = "spec"
+)
+
+// LinkHorizontalPodAutoscalers sets the LinkedObject of every object scaled by a HorizontalPodAutoscaler of the
+// objects. The scale target is matched by the group of its apiVersion, its kind, its name and the namespace.
+// autoscaling/v1 and autoscaling/v2beta2 HorizontalPodAutoscalers and KEDA ScaledObjects are linked as autoscaling/v2,
+// see horizontalPodAutoscaler.
+// The returned warnings list the HorizontalPodAutoscalers whose scale target is not part of the objects.
+func LinkHorizontalPodAutoscalers(objects []ResourceObject) []string {
+	warnings := []string{}
+
+	for _, obj := range objects {
+		hpa, err := horizontalPodAutoscaler(obj.Object)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+
+			continue
+		}
+
+		if hpa == nil {
+			continue
+		}
+
+		found := false
+
+		for i := range objects {
+			if isScaleTarget(hpa.Namespace, hpa.Spec.ScaleTargetRef, objects[i].Object) {
+				objects[i].LinkedObject = hpa
+				found = true
+			}
+		}
+
+		if !found {
+			warnings = append(warnings, fmt.Sprintf("%s %s: scale target %s %s not found",
+				obj.Kind, hpa.Name, hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name))
+		}
+	}
+
+	return warnings
+}
+
+// horizontalPodAutoscaler returns the object as autoscaling/v2 HorizontalPodAutoscaler, which is the common
+// representation of all supported versions and of KEDA ScaledObjects, or nil if the object is none of them. Of older
+// versions only the metadata, the scale target, the replica bounds and the replica status are converted, as the
+// metrics do not matter for the calculation.
+func horizontalPodAutoscaler(obj runtime.Object) (*v2.HorizontalPodAutoscaler, error) {
+	hpa := &v2.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{APIVersion: v2.SchemeGroupVersion.String(), Kind: "HorizontalPodAutoscaler"},
+	}
+
+	switch obj := obj.(type) {
+	case *v2.HorizontalPodAutoscaler:
+		return obj, nil
+	case *autoscalingV1.HorizontalPodAutoscaler:
+		hpa.ObjectMeta = obj.ObjectMeta
+		hpa.Spec.ScaleTargetRef = v2.CrossVersionObjectReference(obj.Spec.ScaleTargetRef)
+		hpa.Spec.MinReplicas = obj.Spec.MinReplicas
+		hpa.Spec.MaxReplicas = obj.Spec.MaxReplicas
+		hpa.Status.CurrentReplicas = obj.Status.CurrentReplicas
+		hpa.Status.DesiredReplicas = obj.Status.DesiredReplicas
+	case *v2beta2.HorizontalPodAutoscaler:
+		hpa.ObjectMeta = obj.ObjectMeta
+		hpa.Spec.ScaleTargetRef = v2.CrossVersionObjectReference(obj.Spec.ScaleTargetRef)
+		hpa.Spec.MinReplicas = obj.Spec.MinReplicas
+		hpa.Spec.MaxReplicas = obj.Spec.MaxReplicas
+		hpa.Status.CurrentReplicas = obj.Status.CurrentReplicas
+		hpa.Status.DesiredReplicas = obj.Status.DesiredReplicas
+	case *unstructured.Unstructured:
+		if obj.GroupVersionKind().GroupKind() == scaledObjectGroupKind {
+			return scaledObjectAutoscaler(obj)
+		}
+
+		return nil, nil
+	default:
+		return nil, nil
+	}
+
+	return hpa, nil
+}
+
+// isScaleTarget reports whether the object is referenced by the scale target of an autoscaler in the given namespace.
+// An empty apiVersion matches any group.
+func isScaleTarget(namespace string, ref v2.CrossVersionObjectReference, obj runtime.Object) bool {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+
+	gvk := obj.GetObjectKind().GroupVersionKind()
+
+	groupVersion, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return false
+	}
+
+	return ref.Kind == gvk.Kind &&
+		ref.Name == accessor.GetName() &&
+		(ref.APIVersion == "" || groupVersion.Group == gvk.Group) &&
+		sameNamespace(namespace, accessor.GetNamespace())
+}
+
+// sameNamespace reports whether two objects are in the same namespace. An empty namespace matches any namespace,
+// as manifests often omit the namespace they are applied to.
+func sameNamespace(a, b string) bool {
+	return a == "" || b == "" || a == b
+}
+
+// hpaReplicas returns the replicas of a workload scaled by the HorizontalPodAutoscaler, if any, and whether it is
+// scaled. The mode selects the min or max replicas of the HorizontalPodAutoscaler or the replicas of the workload.
+func hpaReplicas(replicas int32, hpa *v2.HorizontalPodAutoscaler, mode HpaMode) (int32, bool) {
+	if hpa == nil {
+		return replicas, false
+	}
+
+	switch mode {
+	case HpaModeMin:
+		if hpa.Spec.MinReplicas == nil {
+			// minReplicas defaults to 1
+			return 1, true
+		}
+
+		return *hpa.Spec.MinReplicas, true
+	case HpaModeSpec:
+		return replicas, true
+	default:
+		return hpa.Spec.MaxReplicas, true
+	}
+}
@@ -0,0 +1,117 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+func TestLinkHorizontalPodAutoscalers(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, normalDeployment, normalDeploymentConfig, normalStatefulSet, deploymentConfigHpa, statefulSetHpa, missingTargetHpa)
+
+	warnings := LinkHorizontalPodAutoscalers(objects)
+	r.Equal([]string{"HorizontalPodAutoscaler gone: scale target Deployment gone not found"}, warnings)
+
+	// the deployment has the same name as the deploymentConfig, but is not the scale target
+	r.Nil(objects[0].LinkedObject)
+	r.Equal(objects[3].Object, objects[1].LinkedObject)
+	r.Equal(objects[4].Object, objects[2].LinkedObject)
+
+	usage, err := ResourceQuotaFromYaml(objects[0])
+	r.NoError(err)
+	r.False(usage.Details.Hpa)
+	r.Equal(int32(10), usage.Details.Replicas)
+
+	usage, err = ResourceQuotaFromYaml(objects[1])
+	r.NoError(err)
+	r.True(usage.Details.Hpa)
+	r.Equal(int32(15), usage.Details.Replicas)
+
+	usage, err = ResourceQuotaFromYaml(objects[2])
+	r.NoError(err)
+	r.True(usage.Details.Hpa)
+	r.Equal(int32(4), usage.Details.Replicas)
+}
+
+func TestLinkHorizontalPodAutoscalersNamespace(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, normalStatefulSet, statefulSetHpa)
+
+	for namespace, obj := range map[string]ResourceObject{"team-a": objects[0], "team-b": objects[1]} {
+		accessor, err := meta.Accessor(obj.Object)
+		r.NoError(err)
+		accessor.SetNamespace(namespace)
+	}
+
+	r.Len(LinkHorizontalPodAutoscalers(objects), 1)
+	r.Nil(objects[0].LinkedObject)
+}
+
+func TestLinkHorizontalPodAutoscalersVersions(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, normalDeployment, normalStatefulSet, v1Hpa, v2beta2Hpa)
+
+	r.Empty(LinkHorizontalPodAutoscalers(objects))
+
+	hpa, ok := objects[0].LinkedObject.(*v2.HorizontalPodAutoscaler)
+	r.True(ok)
+	r.Equal("autoscaling/v2", hpa.APIVersion)
+	r.Equal("normal", hpa.Name)
+	r.Equal(int32(2), *hpa.Spec.MinReplicas)
+
+	usage, err := ResourceQuotaFromYaml(objects[0])
+	r.NoError(err)
+	r.True(usage.Details.Hpa)
+	r.Equal(int32(12), usage.Details.Replicas)
+
+	usage, err = ResourceQuotaFromYaml(objects[1])
+	r.NoError(err)
+	r.True(usage.Details.Hpa)
+	r.Equal(int32(5), usage.Details.Replicas)
+}
+
+func TestHpaModes(t *testing.T) {
+	var tests = []struct {
+		name     string
+		mode     HpaMode
+		replicas int32
+	}{
+		{name: "default uses max replicas", mode: "", replicas: 15},
+		{name: "max replicas", mode: HpaModeMax, replicas: 15},
+		{name: "min replicas", mode: HpaModeMin, replicas: 2},
+		{name: "spec replicas", mode: HpaModeSpec, replicas: 10},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+
+			objects := decodeObjects(r, normalDeploymentConfig, deploymentConfigHpa)
+			r.Empty(LinkHorizontalPodAutoscalers(objects))
+
+			objects[0].HpaMode = test.mode
+
+			usage, err := ResourceQuotaFromYaml(objects[0])
+			r.NoError(err)
+			r.True(usage.Details.Hpa)
+			r.Equal(test.replicas, usage.Details.Replicas)
+
+			r.Equal(int32(2), usage.HpaMin.Details.Replicas)
+			r.Equal(int32(15), usage.HpaMax.Details.Replicas)
+			r.Nil(usage.HpaMin.HpaMin)
+		})
+	}
+
+	r := require.New(t)
+
+	usage, err := ResourceQuotaFromYaml(decodeObjects(r, normalDeploymentConfig)[0])
+	r.NoError(err)
+	r.Nil(usage.HpaMin)
+	r.Nil(usage.HpaMax)
+}
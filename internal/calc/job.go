@@ -2,21 +2,73 @@ package calc
 
 import batchV1 "k8s.io/api/batch/v1"
 
+// defaultBackoffLimit is the number of retries of a job without backoffLimit.
+const defaultBackoffLimit = 6
+
+// job calculates the resources of the pods a Job runs at the same time. Replacements of failed pods can start while
+// the previous pods are still terminating, in this case both are part of the max replicas.
 func job(job batchV1.Job) *ResourceUsage {
+	pods, replacements := jobReplicas(&job.Spec)
 	podResources := calcPodResources(&job.Spec.Template.Spec)
 
 	resourceUsage := ResourceUsage{
-		NormalResources:  podResources.Containers,
-		RolloutResources: podResources.MaxResources,
+		NormalResources:  podResources.Containers.MulInt32(pods),
+		RolloutResources: podResources.MaxResources.MulInt32(pods).Add(podResources.Containers.MulInt32(replacements)),
 		Details: Details{
-			Version:     job.APIVersion,
-			Kind:        job.Kind,
-			Name:        job.Name,
-			Strategy:    "",
-			Replicas:    0,
-			MaxReplicas: 0,
+			Version:       job.APIVersion,
+			Kind:          job.Kind,
+			Name:          job.Name,
+			Namespace:     job.Namespace,
+			ResourcesFrom: podResources.ResourcesFrom(),
+			Strategy:      string(podReplacementPolicy(&job.Spec)),
+			Replicas:      pods,
+			MaxReplicas:   pods + replacements,
 		},
 	}
 
 	return &resourceUsage
 }
+
+// jobReplicas returns the number of pods a job runs at the same time and the number of terminating pods that can run
+// next to their replacements. A job runs parallelism pods (defaults to 1), but never more than its completions, which
+// is the number of indexes of an Indexed job. Replacement pods only overlap with terminating ones if failed pods are
+// retried and the TerminatingOrFailed podReplacementPolicy is used.
+func jobReplicas(spec *batchV1.JobSpec) (pods, replacements int32) {
+	pods = 1
+	if spec.Parallelism != nil {
+		pods = *spec.Parallelism
+	}
+
+	if spec.Completions != nil {
+		pods = min(pods, *spec.Completions)
+	}
+
+	// the backoffLimitPerIndex of an Indexed job replaces the global backoffLimit
+	retries := int32(defaultBackoffLimit)
+
+	switch {
+	case spec.BackoffLimitPerIndex != nil:
+		retries = *spec.BackoffLimitPerIndex
+	case spec.BackoffLimit != nil:
+		retries = *spec.BackoffLimit
+	}
+
+	if retries > 0 && podReplacementPolicy(spec) == batchV1.TerminatingOrFailed {
+		replacements = pods
+	}
+
+	return pods, replacements
+}
+
+// podReplacementPolicy returns the podReplacementPolicy of a job, which defaults to Failed if a podFailurePolicy is
+// set and to TerminatingOrFailed otherwise.
+func podReplacementPolicy(spec *batchV1.JobSpec) batchV1.PodReplacementPolicy {
+	switch {
+	case spec.PodReplacementPolicy != nil:
+		return *spec.PodReplacementPolicy
+	case spec.PodFailurePolicy != nil:
+		return batchV1.Failed
+	default:
+		return batchV1.TerminatingOrFailed
+	}
+}
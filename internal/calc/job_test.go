@@ -20,12 +20,48 @@ func TestJob(t *testing.T) {
 		strategy    string
 	}{
 		{
-			name:      "ok",
-			job:       normalJob,
-			cpuMin:    resource.MustParse("250m"),
-			cpuMax:    resource.MustParse("1"),
-			memoryMin: resource.MustParse("2Gi"),
-			memoryMax: resource.MustParse("4Gi"),
+			name:        "ok",
+			job:         normalJob,
+			cpuMin:      resource.MustParse("500m"),
+			cpuMax:      resource.MustParse("2"),
+			memoryMin:   resource.MustParse("4Gi"),
+			memoryMax:   resource.MustParse("8Gi"),
+			replicas:    1,
+			maxReplicas: 2,
+			strategy:    "TerminatingOrFailed",
+		},
+		{
+			name:        "parallelism limited by completions",
+			job:         parallelJob,
+			cpuMin:      resource.MustParse("2500m"),
+			cpuMax:      resource.MustParse("10"),
+			memoryMin:   resource.MustParse("20Gi"),
+			memoryMax:   resource.MustParse("40Gi"),
+			replicas:    5,
+			maxReplicas: 10,
+			strategy:    "TerminatingOrFailed",
+		},
+		{
+			name:        "indexed without retries",
+			job:         indexedJob,
+			cpuMin:      resource.MustParse("750m"),
+			cpuMax:      resource.MustParse("3"),
+			memoryMin:   resource.MustParse("6Gi"),
+			memoryMax:   resource.MustParse("12Gi"),
+			replicas:    3,
+			maxReplicas: 3,
+			strategy:    "TerminatingOrFailed",
+		},
+		{
+			name:        "pod failure policy",
+			job:         podFailurePolicyJob,
+			cpuMin:      resource.MustParse("1"),
+			cpuMax:      resource.MustParse("4"),
+			memoryMin:   resource.MustParse("8Gi"),
+			memoryMax:   resource.MustParse("16Gi"),
+			replicas:    4,
+			maxReplicas: 4,
+			strategy:    "Failed",
 		},
 	}
 
@@ -36,14 +72,14 @@ func TestJob(t *testing.T) {
 
 				resourceObject, kind, version, _ := ConvertToRuntimeObjectFromYaml([]byte(test.job), false)
 
-				usage, err := ResourceQuotaFromYaml(ResourceObject{resourceObject, *kind, *version, nil})
+				usage, err := ResourceQuotaFromYaml(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version})
 				r.NoError(err)
 				r.NotEmpty(usage)
 
-				AssertEqualQuantities(r, test.cpuMin, usage.RolloutResources.CPUMin, "cpu request value")
-				AssertEqualQuantities(r, test.cpuMax, usage.RolloutResources.CPUMax, "cpu limit value")
-				AssertEqualQuantities(r, test.memoryMin, usage.RolloutResources.MemoryMin, "memory request value")
-				AssertEqualQuantities(r, test.memoryMax, usage.RolloutResources.MemoryMax, "memory limit value")
+				AssertEqualQuantities(r, test.cpuMin, *usage.RolloutResources.Requests.Cpu(), "cpu request value")
+				AssertEqualQuantities(r, test.cpuMax, *usage.RolloutResources.Limits.Cpu(), "cpu limit value")
+				AssertEqualQuantities(r, test.memoryMin, *usage.RolloutResources.Requests.Memory(), "memory request value")
+				AssertEqualQuantities(r, test.memoryMax, *usage.RolloutResources.Limits.Memory(), "memory limit value")
 				r.Equalf(test.replicas, usage.Details.Replicas, "replicas")
 				r.Equalf(test.maxReplicas, usage.Details.MaxReplicas, "maxReplicas")
 				r.Equalf(test.strategy, usage.Details.Strategy, "strategy")
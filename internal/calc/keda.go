@@ -0,0 +1,124 @@
+package calc
+
+import (
+	"fmt"
+
+	v2 "k8s.io/api/autoscaling/v2"
+	batchV1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// KEDA defaults, see https://keda.sh/docs/latest/reference/scaledobject-spec/
+const (
+	kedaDefaultMinReplicaCount = 0
+	kedaDefaultMaxReplicaCount = 100
+)
+
+//nolint:gochecknoglobals // read-only lookup table
+var (
+	scaledObjectGroupKind = schema.GroupKind{Group: "keda.sh", Kind: "ScaledObject"}
+	scaledJobGroupKind    = schema.GroupKind{Group: "keda.sh", Kind: "ScaledJob"}
+)
+
+// scaledObjectAutoscaler converts a KEDA ScaledObject to the HorizontalPodAutoscaler KEDA manages for it.
+// The scale target defaults to an apps/v1 Deployment. Unlike a HorizontalPodAutoscaler, a ScaledObject can
+// scale its target to zero.
+func scaledObjectAutoscaler(obj *unstructured.Unstructured) (*v2.HorizontalPodAutoscaler, error) {
+	var spec struct {
+		ScaleTargetRef  v2.CrossVersionObjectReference `json:"scaleTargetRef"`
+		MinReplicaCount *int32                         `json:"minReplicaCount"`
+		MaxReplicaCount *int32                         `json:"maxReplicaCount"`
+	}
+
+	if err := unstructuredSpec(obj, &spec); err != nil {
+		return nil, err
+	}
+
+	hpa := &v2.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{APIVersion: v2.SchemeGroupVersion.String(), Kind: "HorizontalPodAutoscaler"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+		},
+		Spec: v2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: spec.ScaleTargetRef,
+			MinReplicas:    spec.MinReplicaCount,
+			MaxReplicas:    kedaDefaultMaxReplicaCount,
+		},
+	}
+
+	if hpa.Spec.ScaleTargetRef.APIVersion == "" {
+		hpa.Spec.ScaleTargetRef.APIVersion = "apps/v1"
+	}
+
+	if hpa.Spec.ScaleTargetRef.Kind == "" {
+		hpa.Spec.ScaleTargetRef.Kind = "Deployment"
+	}
+
+	if hpa.Spec.MinReplicas == nil {
+		minReplicas := int32(kedaDefaultMinReplicaCount)
+		hpa.Spec.MinReplicas = &minReplicas
+	}
+
+	if spec.MaxReplicaCount != nil {
+		hpa.Spec.MaxReplicas = *spec.MaxReplicaCount
+	}
+
+	return hpa, nil
+}
+
+// scaledJob calculates the peak usage of a KEDA ScaledJob, which runs up to maxReplicaCount jobs of its job
+// template at the same time.
+func scaledJob(obj *unstructured.Unstructured) (*ResourceUsage, error) {
+	var spec struct {
+		JobTargetRef    batchV1.JobSpec `json:"jobTargetRef"`
+		MaxReplicaCount *int32          `json:"maxReplicaCount"`
+	}
+
+	if err := unstructuredSpec(obj, &spec); err != nil {
+		return nil, err
+	}
+
+	jobs := int32(kedaDefaultMaxReplicaCount)
+	if spec.MaxReplicaCount != nil {
+		jobs = *spec.MaxReplicaCount
+	}
+
+	pods, replacements := jobReplicas(&spec.JobTargetRef)
+	pods, replacements = jobs*pods, jobs*replacements
+	podResources := calcPodResources(&spec.JobTargetRef.Template.Spec)
+
+	resourceUsage := ResourceUsage{
+		NormalResources:  podResources.Containers.MulInt32(pods),
+		RolloutResources: podResources.MaxResources.MulInt32(pods).Add(podResources.Containers.MulInt32(replacements)),
+		Details: Details{
+			Version:       obj.GetAPIVersion(),
+			Kind:          obj.GetKind(),
+			Name:          obj.GetName(),
+			Namespace:     obj.GetNamespace(),
+			ResourcesFrom: podResources.ResourcesFrom(),
+			Strategy:      "",
+			Replicas:      pods,
+			MaxReplicas:   pods + replacements,
+		},
+	}
+
+	return &resourceUsage, nil
+}
+
+// unstructuredSpec converts the spec of an unstructured object to the given struct.
+func unstructuredSpec(obj *unstructured.Unstructured, spec any) error {
+	specMap, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(specMap, spec); err != nil {
+		return fmt.Errorf("%s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+
+	return nil
+}
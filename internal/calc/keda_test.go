@@ -0,0 +1,63 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestScaledObject(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, normalDeployment, deploymentScaledObject, normalScaledObject)
+
+	warnings := LinkHorizontalPodAutoscalers(objects)
+	r.Equal([]string{"ScaledObject myscaledobject: scale target Deployment myapp not found"}, warnings)
+
+	hpa, ok := objects[0].LinkedObject.(*v2.HorizontalPodAutoscaler)
+	r.True(ok)
+	r.Equal("consumer", hpa.Name)
+	r.Equal("apps/v1", hpa.Spec.ScaleTargetRef.APIVersion)
+
+	usage, err := ResourceQuotaFromYaml(objects[0])
+	r.NoError(err)
+	r.True(usage.Details.Hpa)
+	r.Equal(int32(20), usage.Details.Replicas)
+	r.Equal(int32(1), usage.HpaMin.Details.Replicas)
+
+	_, err = ResourceQuotaFromYaml(objects[1])
+	r.ErrorIs(err, ErrResourceNotSupported)
+}
+
+func TestScaledObjectDefaults(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, normalScaledObject)
+
+	hpa, err := horizontalPodAutoscaler(objects[0].Object)
+	r.NoError(err)
+	r.Equal("Deployment", hpa.Spec.ScaleTargetRef.Kind)
+	r.Equal("myapp", hpa.Spec.ScaleTargetRef.Name)
+	r.Equal(int32(0), *hpa.Spec.MinReplicas)
+	r.Equal(int32(100), hpa.Spec.MaxReplicas)
+}
+
+func TestScaledJob(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, normalScaledJob)
+
+	usage, err := ResourceQuotaFromYaml(objects[0])
+	r.NoError(err)
+	r.Equal("ScaledJob", usage.Details.Kind)
+	r.Equal(int32(10), usage.Details.Replicas)
+	// replacement pods can run next to terminating ones
+	r.Equal(int32(20), usage.Details.MaxReplicas)
+
+	AssertEqualQuantities(r, resource.MustParse("2"), *usage.RolloutResources.Requests.Cpu(), "cpu request value")
+	AssertEqualQuantities(r, resource.MustParse("4"), *usage.RolloutResources.Limits.Cpu(), "cpu limit value")
+	AssertEqualQuantities(r, resource.MustParse("1280Mi"), *usage.RolloutResources.Requests.Memory(), "memory request value")
+	AssertEqualQuantities(r, resource.MustParse("2560Mi"), *usage.RolloutResources.Limits.Memory(), "memory limit value")
+}
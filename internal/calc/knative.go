@@ -0,0 +1,300 @@
+package calc
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	v2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Knative annotations and defaults, see https://knative.dev/docs/serving/autoscaling/scale-bounds/ and
+// https://knative.dev/docs/serving/configuration/deployment/
+const (
+	knativeMinScaleAnnotation     = "autoscaling.knative.dev/min-scale"
+	knativeMaxScaleAnnotation     = "autoscaling.knative.dev/max-scale"
+	knativeInitialScaleAnnotation = "autoscaling.knative.dev/initial-scale"
+	queueSidecarAnnotationPrefix  = "queue.sidecar.serving.knative.dev/"
+	queueProxyDefaultCPURequest   = "25m"
+	knativeDeploymentConfigMap    = "config-deployment"
+	queueSidecarConfigPrefix      = "queue-sidecar-"
+)
+
+//nolint:gochecknoglobals // read-only lookup table
+var knativeServiceGroupKind = schema.GroupKind{Group: "serving.knative.dev", Kind: "Service"}
+
+// knativeScaleAnnotations are the current and the legacy names of the scale bound annotations.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var knativeScaleAnnotations = map[string]string{
+	knativeMinScaleAnnotation:     "autoscaling.knative.dev/minScale",
+	knativeMaxScaleAnnotation:     "autoscaling.knative.dev/maxScale",
+	knativeInitialScaleAnnotation: "autoscaling.knative.dev/initialScale",
+}
+
+// queueProxyResourceAnnotations are the annotations setting the resources of the queue-proxy sidecar.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var queueProxyResourceAnnotations = map[v1.ResourceName]string{
+	v1.ResourceCPU:              "cpu-resource",
+	v1.ResourceMemory:           "memory-resource",
+	v1.ResourceEphemeralStorage: "ephemeral-storage-resource",
+}
+
+// queueProxyResourceConfigKeys are the keys of the config-deployment ConfigMap setting the resources of the
+// queue-proxy sidecar for the whole cluster.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var queueProxyResourceConfigKeys = map[v1.ResourceName]string{
+	v1.ResourceCPU:              "cpu",
+	v1.ResourceMemory:           "memory",
+	v1.ResourceEphemeralStorage: "ephemeral-storage",
+}
+
+// knativeServiceSpec contains the fields of a Knative Service needed for the calculation,
+// see https://knative.dev/docs/serving/reference/serving-api/#serving.knative.dev/v1.Service
+type knativeServiceSpec struct {
+	Template struct {
+		Metadata metav1.ObjectMeta `json:"metadata"`
+		Spec     v1.PodSpec        `json:"spec"`
+	} `json:"template"`
+	Traffic []knativeTrafficTarget `json:"traffic"`
+}
+
+type knativeTrafficTarget struct {
+	RevisionName   string `json:"revisionName"`
+	LatestRevision *bool  `json:"latestRevision"`
+	Percent        *int64 `json:"percent"`
+}
+
+// knativeService calculates the resources a Knative Service needs. The replicas are taken from the scale bound
+// annotations like the ones of a HorizontalPodAutoscaler and split across the revisions of the traffic block. The
+// queue-proxy sidecar injected by Knative is added to every pod, starting from the resources configured for the
+// cluster (nil for the Knative defaults). On a rollout, a new revision is started next to the revisions serving
+// traffic.
+func knativeService(
+	obj *unstructured.Unstructured, hpaMode HpaMode, queueProxyResources *v1.ResourceRequirements,
+) (*ResourceUsage, error) {
+	var spec knativeServiceSpec
+
+	if err := unstructuredSpec(obj, &spec); err != nil {
+		return nil, err
+	}
+
+	annotations := spec.Template.Metadata.Annotations
+
+	hpa, initialScale, err := knativeAutoscaler(annotations)
+	if err != nil {
+		return nil, fmt.Errorf("knative service: %s: %w", obj.GetName(), err)
+	}
+
+	queueProxy, err := queueProxyContainer(annotations, queueProxyResources)
+	if err != nil {
+		return nil, fmt.Errorf("knative service: %s: %w", obj.GetName(), err)
+	}
+
+	scale, _ := hpaReplicas(initialScale, hpa, hpaMode)
+	replicas, latestReplicas := revisionReplicas(spec.Traffic, scale, *hpa.Spec.MinReplicas)
+
+	podSpec := spec.Template.Spec.DeepCopy()
+	podSpec.Containers = append(podSpec.Containers, queueProxy)
+
+	podResources := calcPodResources(podSpec)
+	normalResources := podResources.Containers.MulInt32(replicas)
+	rolloutResources := normalResources.Add(podResources.MaxResources.MulInt32(latestReplicas))
+
+	resourceUsage := ResourceUsage{
+		NormalResources:  normalResources,
+		RolloutResources: rolloutResources,
+		Details: Details{
+			Version:       obj.GetAPIVersion(),
+			Kind:          obj.GetKind(),
+			Name:          obj.GetName(),
+			Namespace:     obj.GetNamespace(),
+			ResourcesFrom: podResources.ResourcesFrom(),
+			Strategy:      "",
+			Replicas:      replicas,
+			MaxReplicas:   replicas + latestReplicas,
+			Hpa:           true,
+		},
+	}
+
+	return &resourceUsage, nil
+}
+
+// knativeAutoscaler converts the scale bound annotations of a revision template to a HorizontalPodAutoscaler and
+// returns the initial scale, which defaults to 1. min-scale defaults to 0. A max-scale of 0 is unbounded, in this
+// case the revision is calculated with its initial or min scale.
+func knativeAutoscaler(annotations map[string]string) (*v2.HorizontalPodAutoscaler, int32, error) {
+	minScale, err := knativeScale(annotations, knativeMinScaleAnnotation, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	maxScale, err := knativeScale(annotations, knativeMaxScaleAnnotation, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	initialScale, err := knativeScale(annotations, knativeInitialScaleAnnotation, 1)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if maxScale == 0 {
+		maxScale = max(initialScale, minScale)
+	}
+
+	hpa := &v2.HorizontalPodAutoscaler{
+		Spec: v2.HorizontalPodAutoscalerSpec{
+			MinReplicas: &minScale,
+			MaxReplicas: maxScale,
+		},
+	}
+
+	return hpa, initialScale, nil
+}
+
+// knativeScale parses a scale bound annotation, the legacy camel case name is used as a fallback.
+func knativeScale(annotations map[string]string, name string, defaultScale int32) (int32, error) {
+	value, ok := annotations[name]
+	if !ok {
+		value, ok = annotations[knativeScaleAnnotations[name]]
+	}
+
+	if !ok {
+		return defaultScale, nil
+	}
+
+	scale, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || scale < 0 {
+		return 0, fmt.Errorf("invalid annotation %s: %q", name, value)
+	}
+
+	return int32(scale), nil
+}
+
+// QueueProxyResources returns the resources of the queue-proxy sidecar Knative injects into every pod, configured for
+// the whole cluster by the queue-sidecar-* keys of the config-deployment ConfigMap, e.g. queue-sidecar-memory-limit.
+// Other ConfigMaps are ignored. Without the keys, the sidecar requests the Knative default of 25m cpu.
+func QueueProxyResources(configMaps []*v1.ConfigMap) (*v1.ResourceRequirements, error) {
+	resources := &v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(queueProxyDefaultCPURequest)},
+		Limits:   v1.ResourceList{},
+	}
+
+	for _, configMap := range configMaps {
+		if configMap.Name != knativeDeploymentConfigMap {
+			continue
+		}
+
+		err := setQueueProxyResources(resources, configMap.Data, queueSidecarConfigPrefix, queueProxyResourceConfigKeys)
+		if err != nil {
+			return nil, fmt.Errorf("configmap %s: %w", configMap.Name, err)
+		}
+	}
+
+	return resources, nil
+}
+
+// queueProxyContainer returns the queue-proxy sidecar Knative injects into every pod. The resources configured for
+// the cluster (nil for the Knative defaults) are overridden by the queue.sidecar.serving.knative.dev annotations.
+func queueProxyContainer(annotations map[string]string, resources *v1.ResourceRequirements) (v1.Container, error) {
+	if resources == nil {
+		resources, _ = QueueProxyResources(nil)
+	}
+
+	container := v1.Container{
+		Name: "queue-proxy",
+		Resources: v1.ResourceRequirements{
+			Requests: resources.Requests.DeepCopy(),
+			Limits:   resources.Limits.DeepCopy(),
+		},
+	}
+
+	if container.Resources.Requests == nil {
+		container.Resources.Requests = v1.ResourceList{}
+	}
+
+	if container.Resources.Limits == nil {
+		container.Resources.Limits = v1.ResourceList{}
+	}
+
+	err := setQueueProxyResources(&container.Resources, annotations, queueSidecarAnnotationPrefix, queueProxyResourceAnnotations)
+	if err != nil {
+		return v1.Container{}, err
+	}
+
+	return container, nil
+}
+
+// setQueueProxyResources sets the requests and limits of the <prefix><key>-request and <prefix><key>-limit values.
+func setQueueProxyResources(
+	resources *v1.ResourceRequirements, values map[string]string, prefix string, keys map[v1.ResourceName]string,
+) error {
+	for name, key := range keys {
+		for suffix, list := range map[string]v1.ResourceList{
+			"-request": resources.Requests,
+			"-limit":   resources.Limits,
+		} {
+			key := prefix + key + suffix
+
+			value, ok := values[key]
+			if !ok {
+				continue
+			}
+
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %w", key, err)
+			}
+
+			list[name] = quantity
+		}
+	}
+
+	return nil
+}
+
+// revisionReplicas splits the replicas across the revisions of the traffic block by their percent, every revision
+// runs at least minScale pods. Without a traffic block, the latest revision gets all traffic. It returns the
+// replicas of all revisions and the ones of the latest revision.
+func revisionReplicas(traffic []knativeTrafficTarget, replicas, minScale int32) (total, latest int32) {
+	if len(traffic) == 0 {
+		return max(replicas, minScale), max(replicas, minScale)
+	}
+
+	// revisions can be referenced multiple times, e.g. with different tags
+	percents := map[string]int64{}
+
+	for _, target := range traffic {
+		// latestRevision defaults to true if no revisionName is set
+		revision := target.RevisionName
+		if target.LatestRevision != nil && *target.LatestRevision {
+			revision = ""
+		}
+
+		var percent int64
+		if target.Percent != nil {
+			percent = *target.Percent
+		}
+
+		percents[revision] += percent
+	}
+
+	for revision, percent := range percents {
+		revisionReplicas := max(int32(math.Ceil(float64(percent)*float64(replicas)/100)), minScale)
+		total += revisionReplicas
+
+		if revision == "" {
+			latest = revisionReplicas
+		}
+	}
+
+	return total, latest
+}
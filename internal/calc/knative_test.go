@@ -0,0 +1,98 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestKnativeService(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, normalKnativeService)
+
+	usage, err := ResourceQuotaFromYaml(objects[0])
+	r.NoError(err)
+	r.Equal("Service", usage.Details.Kind)
+	r.True(usage.Details.Hpa)
+
+	// 8 pods of the latest revision and 2 of the previous one, a new revision is started on a rollout
+	r.Equal(int32(10), usage.Details.Replicas)
+	r.Equal(int32(18), usage.Details.MaxReplicas)
+
+	// the queue-proxy requests 25m cpu by default and 64Mi memory by annotation
+	AssertEqualQuantities(r, resource.MustParse("1250m"), *usage.NormalResources.Requests.Cpu(), "cpu request value")
+	AssertEqualQuantities(r, resource.MustParse("2"), *usage.NormalResources.Limits.Cpu(), "cpu limit value")
+	AssertEqualQuantities(r, resource.MustParse("1920Mi"), *usage.NormalResources.Requests.Memory(), "memory request value")
+	AssertEqualQuantities(r, resource.MustParse("2250m"), *usage.RolloutResources.Requests.Cpu(), "cpu request value")
+
+	// every revision keeps at least min-scale pods
+	r.Equal(int32(4), usage.HpaMin.Details.Replicas)
+	r.Equal(int32(10), usage.HpaMax.Details.Replicas)
+}
+
+func TestKnativeServiceDefaults(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, defaultKnativeService)
+
+	usage, err := ResourceQuotaFromYaml(objects[0])
+	r.NoError(err)
+	r.Equal(int32(1), usage.Details.Replicas)
+	r.Equal(int32(2), usage.Details.MaxReplicas)
+	r.Equal(int32(0), usage.HpaMin.Details.Replicas)
+
+	AssertEqualQuantities(r, resource.MustParse("125m"), *usage.NormalResources.Requests.Cpu(), "cpu request value")
+	AssertEqualQuantities(r, resource.MustParse("128Mi"), *usage.NormalResources.Requests.Memory(), "memory request value")
+}
+
+func TestQueueProxyResources(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, normalConfigMap, knativeDeploymentConfig, invalidKnativeDeploymentConfig)
+	configMaps := []*v1.ConfigMap{}
+
+	for _, obj := range objects {
+		configMaps = append(configMaps, obj.Object.(*v1.ConfigMap))
+	}
+
+	// the Knative default without a config-deployment ConfigMap
+	resources, err := QueueProxyResources(configMaps[:1])
+	r.NoError(err)
+	AssertEqualQuantities(r, resource.MustParse("25m"), *resources.Requests.Cpu(), "default cpu request value")
+	r.Empty(resources.Limits)
+
+	_, err = QueueProxyResources(configMaps)
+	r.ErrorContains(err, "invalid queue-sidecar-memory-limit")
+
+	resources, err = QueueProxyResources(configMaps[:2])
+	r.NoError(err)
+
+	objects = decodeObjects(r, normalKnativeService)
+	objects[0].QueueProxy = resources
+
+	usage, err := ResourceQuotaFromYaml(objects[0])
+	r.NoError(err)
+
+	// 10 pods, the memory request annotation overrides the one of the ConfigMap
+	AssertEqualQuantities(r, resource.MustParse("2"), *usage.NormalResources.Requests.Cpu(), "cpu request value")
+	AssertEqualQuantities(r, resource.MustParse("7"), *usage.NormalResources.Limits.Cpu(), "cpu limit value")
+	AssertEqualQuantities(r, resource.MustParse("1920Mi"), *usage.NormalResources.Requests.Memory(), "memory request value")
+	AssertEqualQuantities(r, resource.MustParse("3840Mi"), *usage.NormalResources.Limits.Memory(), "memory limit value")
+
+	// the configured resources are not changed by the annotations
+	AssertEqualQuantities(r, resource.MustParse("32Mi"), *resources.Requests.Memory(), "configured memory request value")
+}
+
+func TestKnativeScale(t *testing.T) {
+	r := require.New(t)
+
+	scale, err := knativeScale(map[string]string{"autoscaling.knative.dev/maxScale": "5"}, knativeMaxScaleAnnotation, 0)
+	r.NoError(err)
+	r.Equal(int32(5), scale)
+
+	_, err = knativeScale(map[string]string{knativeMaxScaleAnnotation: "many"}, knativeMaxScaleAnnotation, 0)
+	r.Error(err)
+}
@@ -0,0 +1,220 @@
+package calc
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ApplyLimitRanges applies the container defaults of the LimitRanges to the pod template of a copy of the object,
+// just like the LimitRanger admission controller does. LimitRanges apply to objects of the same namespace,
+// a LimitRange or object without namespace matches any namespace.
+// The returned violations describe the containers and pods that would be rejected because of the min, max or
+// maxLimitRequestRatio constraints of a LimitRange.
+func ApplyLimitRanges(resourceObject ResourceObject, limitRanges []*v1.LimitRange) (ResourceObject, []string) {
+	accessor, err := meta.Accessor(resourceObject.Object)
+	if err != nil {
+		return resourceObject, nil
+	}
+
+	items := []v1.LimitRangeItem{}
+
+	for _, limitRange := range limitRanges {
+		if sameNamespace(limitRange.Namespace, accessor.GetNamespace()) {
+			items = append(items, limitRange.Spec.Limits...)
+		}
+	}
+
+	if len(items) == 0 {
+		return resourceObject, nil
+	}
+
+	name := fmt.Sprintf("%s %s", resourceObject.Kind, accessor.GetName())
+	violations := []string{}
+
+	updated, ok := updatePodSpecs(resourceObject, func(spec *v1.PodSpec) {
+		violations = append(violations, applyPodLimitRanges(spec, items, name)...)
+	})
+	if !ok {
+		return resourceObject, nil
+	}
+
+	return updated, violations
+}
+
+// applyPodLimitRanges applies the container defaults of the LimitRange items to the pod spec and returns the
+// violations of the containers and the pod.
+func applyPodLimitRanges(spec *v1.PodSpec, items []v1.LimitRangeItem, name string) []string {
+	violations := []string{}
+
+	for _, containers := range [][]v1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			applyContainerDefaults(&containers[i].Resources, items)
+
+			for _, violation := range limitRangeViolations(v1.LimitTypeContainer, &containers[i].Resources, items) {
+				violations = append(violations, fmt.Sprintf("%s: container %s: %s", name, containers[i].Name, violation))
+			}
+		}
+	}
+
+	podResources := calcPodResources(spec).MaxResources
+	podRequirements := v1.ResourceRequirements{Requests: podResources.Requests, Limits: podResources.Limits}
+
+	for _, violation := range limitRangeViolations(v1.LimitTypePod, &podRequirements, items) {
+		violations = append(violations, fmt.Sprintf("%s: %s", name, violation))
+	}
+
+	return violations
+}
+
+// applyContainerDefaults sets the missing requests and limits of a container. A missing request defaults to the
+// limit of the container, as the API server does before admission. Afterwards the default limits and requests of
+// the LimitRanges are applied, the first LimitRange setting a value wins.
+func applyContainerDefaults(requirements *v1.ResourceRequirements, items []v1.LimitRangeItem) {
+	if requirements.Requests == nil {
+		requirements.Requests = v1.ResourceList{}
+	}
+
+	if requirements.Limits == nil {
+		requirements.Limits = v1.ResourceList{}
+	}
+
+	for name, limit := range requirements.Limits {
+		if _, ok := requirements.Requests[name]; !ok {
+			requirements.Requests[name] = limit.DeepCopy()
+		}
+	}
+
+	for _, item := range items {
+		if item.Type != v1.LimitTypeContainer {
+			continue
+		}
+
+		defaultLimits, defaultRequests := containerDefaults(item)
+
+		for name, limit := range defaultLimits {
+			if _, ok := requirements.Limits[name]; !ok {
+				requirements.Limits[name] = limit.DeepCopy()
+			}
+		}
+
+		for name, request := range defaultRequests {
+			if _, ok := requirements.Requests[name]; !ok {
+				requirements.Requests[name] = request.DeepCopy()
+			}
+		}
+	}
+}
+
+// containerDefaults returns the default limits and requests of a container LimitRangeItem, including the values
+// the API server derives: a missing default limit defaults to max, a missing default request to the default limit
+// or else to min.
+func containerDefaults(item v1.LimitRangeItem) (v1.ResourceList, v1.ResourceList) {
+	limits := addResourceList(nil, item.Default)
+	requests := addResourceList(nil, item.DefaultRequest)
+
+	for name, value := range item.Max {
+		if _, ok := limits[name]; !ok {
+			limits[name] = value.DeepCopy()
+		}
+	}
+
+	for _, list := range []v1.ResourceList{limits, item.Min} {
+		for name, value := range list {
+			if _, ok := requests[name]; !ok {
+				requests[name] = value.DeepCopy()
+			}
+		}
+	}
+
+	return limits, requests
+}
+
+// limitRangeViolations returns the min, max and maxLimitRequestRatio constraints of the LimitRangeItems of the
+// given type, which the requirements violate. The messages follow the ones of the LimitRanger admission controller.
+func limitRangeViolations(limitType v1.LimitType, requirements *v1.ResourceRequirements, items []v1.LimitRangeItem) []string {
+	violations := []string{}
+
+	for _, item := range items {
+		if item.Type != limitType {
+			continue
+		}
+
+		for _, name := range (Resources{Requests: item.Min}).ResourceNames() {
+			value := item.Min[name]
+
+			if violation := minViolation(name, value, requirements); violation != "" {
+				violations = append(violations, fmt.Sprintf("minimum %s usage per %s is %s, but %s", name, limitType, value.String(), violation))
+			}
+		}
+
+		for _, name := range (Resources{Requests: item.Max}).ResourceNames() {
+			value := item.Max[name]
+
+			if violation := maxViolation(name, value, requirements); violation != "" {
+				violations = append(violations, fmt.Sprintf("maximum %s usage per %s is %s, but %s", name, limitType, value.String(), violation))
+			}
+		}
+
+		for _, name := range (Resources{Requests: item.MaxLimitRequestRatio}).ResourceNames() {
+			value := item.MaxLimitRequestRatio[name]
+
+			if violation := ratioViolation(name, value, requirements); violation != "" {
+				violations = append(violations, fmt.Sprintf("%s max limit to request ratio per %s is %s, but %s",
+					name, limitType, value.String(), violation))
+			}
+		}
+	}
+
+	return violations
+}
+
+func minViolation(name v1.ResourceName, minimum resource.Quantity, requirements *v1.ResourceRequirements) string {
+	request, hasRequest := requirements.Requests[name]
+	limit, hasLimit := requirements.Limits[name]
+
+	switch {
+	case !hasRequest:
+		return "no request is specified"
+	case request.Cmp(minimum) < 0:
+		return "request is " + request.String()
+	case hasLimit && limit.Cmp(minimum) < 0:
+		return "limit is " + limit.String()
+	default:
+		return ""
+	}
+}
+
+func maxViolation(name v1.ResourceName, maximum resource.Quantity, requirements *v1.ResourceRequirements) string {
+	request, hasRequest := requirements.Requests[name]
+	limit, hasLimit := requirements.Limits[name]
+
+	switch {
+	case !hasLimit:
+		return "no limit is specified"
+	case limit.Cmp(maximum) > 0:
+		return "limit is " + limit.String()
+	case hasRequest && request.Cmp(maximum) > 0:
+		return "request is " + request.String()
+	default:
+		return ""
+	}
+}
+
+func ratioViolation(name v1.ResourceName, ratio resource.Quantity, requirements *v1.ResourceRequirements) string {
+	request, hasRequest := requirements.Requests[name]
+	limit, hasLimit := requirements.Limits[name]
+
+	switch {
+	case !hasRequest || request.IsZero():
+		return "no request is specified"
+	case !hasLimit:
+		return "no limit is specified"
+	case float64(limit.MilliValue())/float64(request.MilliValue()) > ratio.AsApproximateFloat64():
+		return fmt.Sprintf("provided ratio is %s/%s", limit.String(), request.String())
+	default:
+		return ""
+	}
+}
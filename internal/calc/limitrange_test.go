@@ -0,0 +1,84 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func limitRangeObjects(r *require.Assertions, namespace string) (ResourceObject, *v1.LimitRange) {
+	podObject, kind, version, err := ConvertToRuntimeObjectFromYaml([]byte(noResourcesPod), false)
+	r.NoError(err)
+
+	limitRangeObject, _, _, err := ConvertToRuntimeObjectFromYaml([]byte(limitRange), false)
+	r.NoError(err)
+
+	lr, ok := limitRangeObject.(*v1.LimitRange)
+	r.True(ok)
+	lr.Namespace = namespace
+
+	return ResourceObject{Object: podObject, Kind: *kind, Version: *version}, lr
+}
+
+func TestApplyLimitRanges(t *testing.T) {
+	r := require.New(t)
+
+	object, lr := limitRangeObjects(r, "")
+
+	defaulted, violations := ApplyLimitRanges(object, []*v1.LimitRange{lr})
+	r.Equal([]string{
+		"Pod defaults: container greedy: maximum memory usage per Container is 2Gi, but limit is 4Gi",
+		"Pod defaults: container greedy: cpu max limit to request ratio per Container is 4, but provided ratio is 1/100m",
+		"Pod defaults: maximum memory usage per Pod is 3Gi, but limit is 4Gi",
+	}, violations)
+
+	usage, err := ResourceQuotaFromYaml(defaulted)
+	r.NoError(err)
+	AssertEqualQuantities(r, resource.MustParse("1250m"), *usage.NormalResources.Requests.Cpu(), "cpu request value")
+	AssertEqualQuantities(r, resource.MustParse("1500m"), *usage.NormalResources.Limits.Cpu(), "cpu limit value")
+	AssertEqualQuantities(r, resource.MustParse("1280Mi"), *usage.NormalResources.Requests.Memory(), "memory request value")
+	AssertEqualQuantities(r, resource.MustParse("1536Mi"), *usage.NormalResources.Limits.Memory(), "memory limit value")
+	AssertEqualQuantities(r, resource.MustParse("4Gi"), *usage.RolloutResources.Requests.Memory(), "init memory request value")
+
+	// the original object is left untouched
+	usage, err = ResourceQuotaFromYaml(object)
+	r.NoError(err)
+	r.True(usage.NormalResources.Requests.Cpu().IsZero())
+	AssertEqualQuantities(r, resource.MustParse("1Gi"), *usage.NormalResources.Limits.Memory(), "original memory limit value")
+}
+
+func TestApplyLimitRangesNamespace(t *testing.T) {
+	r := require.New(t)
+
+	object, lr := limitRangeObjects(r, "team-b")
+
+	pod, ok := object.Object.(*v1.Pod)
+	r.True(ok)
+	pod.Namespace = "team-a"
+
+	defaulted, violations := ApplyLimitRanges(object, []*v1.LimitRange{lr})
+	r.Empty(violations)
+	r.Same(object.Object, defaulted.Object)
+
+	pod.Namespace = "team-b"
+
+	_, violations = ApplyLimitRanges(object, []*v1.LimitRange{lr})
+	r.Len(violations, 3)
+}
+
+func TestContainerDefaults(t *testing.T) {
+	r := require.New(t)
+
+	limits, requests := containerDefaults(v1.LimitRangeItem{
+		Type: v1.LimitTypeContainer,
+		Max:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+		Min:  v1.ResourceList{v1.ResourceMemory: resource.MustParse("64Mi")},
+	})
+
+	AssertEqualQuantities(r, resource.MustParse("2"), *limits.Cpu(), "default cpu limit value")
+	AssertEqualQuantities(r, resource.MustParse("2"), *requests.Cpu(), "default cpu request value")
+	AssertEqualQuantities(r, resource.MustParse("64Mi"), *requests.Memory(), "default memory request value")
+	r.NotContains(limits, v1.ResourceMemory)
+}
@@ -0,0 +1,144 @@
+package calc
+
+import (
+	"slices"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// daemonSetTolerations are added to every DaemonSet pod by the DaemonSet controller, see
+// https://kubernetes.io/docs/concepts/workloads/controllers/daemonset/#taints-and-tolerations
+//
+//nolint:gochecknoglobals // read-only lookup table
+var daemonSetTolerations = []v1.Toleration{
+	{Key: v1.TaintNodeNotReady, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoExecute},
+	{Key: v1.TaintNodeUnreachable, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoExecute},
+	{Key: v1.TaintNodeDiskPressure, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+	{Key: v1.TaintNodeMemoryPressure, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+	{Key: v1.TaintNodePIDPressure, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+	{Key: v1.TaintNodeUnschedulable, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+}
+
+// daemonSetNodeCount returns the number of nodes the pods of a DaemonSet are scheduled to. If nodes are given, the
+// ones matching the nodeSelector, the required node affinity and tolerating the taints of the node are counted.
+// Otherwise, the DaemonSet runs on all nodeCount nodes, or on a single node if the nodes are unknown.
+func daemonSetNodeCount(podSpec *v1.PodSpec, nodes []*v1.Node, nodeCount int32) int32 {
+	if len(nodes) == 0 {
+		return max(1, nodeCount)
+	}
+
+	tolerations := slices.Clone(daemonSetTolerations)
+	tolerations = append(tolerations, podSpec.Tolerations...)
+
+	if podSpec.HostNetwork {
+		tolerations = append(tolerations, v1.Toleration{
+			Key: v1.TaintNodeNetworkUnavailable, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule,
+		})
+	}
+
+	var count int32
+
+	for _, node := range nodes {
+		if labels.SelectorFromSet(podSpec.NodeSelector).Matches(labels.Set(node.Labels)) &&
+			matchesNodeAffinity(podSpec.Affinity, node) &&
+			toleratesTaints(tolerations, node.Spec.Taints) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// matchesNodeAffinity reports whether the node matches any term of the required node affinity.
+func matchesNodeAffinity(affinity *v1.Affinity, node *v1.Node) bool {
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if matchesNodeSelectorTerm(term, node) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesNodeSelectorTerm reports whether the node matches all requirements of the term. An empty term matches no
+// node. metadata.name is the only field supported by matchFields.
+func matchesNodeSelectorTerm(term v1.NodeSelectorTerm, node *v1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+
+	for _, requirement := range term.MatchExpressions {
+		value, ok := node.Labels[requirement.Key]
+		if !matchesNodeSelectorRequirement(requirement, value, ok) {
+			return false
+		}
+	}
+
+	for _, requirement := range term.MatchFields {
+		if requirement.Key != "metadata.name" || !matchesNodeSelectorRequirement(requirement, node.Name, true) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesNodeSelectorRequirement reports whether the value of a node label or field satisfies the requirement.
+func matchesNodeSelectorRequirement(requirement v1.NodeSelectorRequirement, value string, exists bool) bool {
+	switch requirement.Operator {
+	case v1.NodeSelectorOpIn:
+		return exists && slices.Contains(requirement.Values, value)
+	case v1.NodeSelectorOpNotIn:
+		return !exists || !slices.Contains(requirement.Values, value)
+	case v1.NodeSelectorOpExists:
+		return exists
+	case v1.NodeSelectorOpDoesNotExist:
+		return !exists
+	case v1.NodeSelectorOpGt, v1.NodeSelectorOpLt:
+		if !exists || len(requirement.Values) != 1 {
+			return false
+		}
+
+		actual, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+
+		expected, err := strconv.ParseInt(requirement.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+
+		if requirement.Operator == v1.NodeSelectorOpGt {
+			return actual > expected
+		}
+
+		return actual < expected
+	default:
+		return false
+	}
+}
+
+// toleratesTaints reports whether all NoSchedule and NoExecute taints are tolerated. PreferNoSchedule taints do not
+// prevent scheduling.
+func toleratesTaints(tolerations []v1.Toleration, taints []v1.Taint) bool {
+	for i := range taints {
+		if taints[i].Effect == v1.TaintEffectPreferNoSchedule {
+			continue
+		}
+
+		if !slices.ContainsFunc(tolerations, func(toleration v1.Toleration) bool {
+			return toleration.ToleratesTaint(&taints[i])
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
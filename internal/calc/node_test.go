@@ -0,0 +1,103 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func nodes(r *require.Assertions) []*v1.Node {
+	result := []*v1.Node{}
+
+	for _, obj := range decodeObjects(r, clusterNodes...) {
+		node, ok := obj.Object.(*v1.Node)
+		r.True(ok)
+
+		result = append(result, node)
+	}
+
+	return result
+}
+
+func TestDaemonSetNodeCount(t *testing.T) {
+	var tests = []struct {
+		name    string
+		podSpec v1.PodSpec
+		count   int32
+	}{
+		{
+			name:    "tolerates cordoned nodes",
+			podSpec: v1.PodSpec{},
+			count:   2,
+		},
+		{
+			name:    "node selector",
+			podSpec: v1.PodSpec{NodeSelector: map[string]string{"zone": "a"}},
+			count:   1,
+		},
+		{
+			name: "tolerations",
+			podSpec: v1.PodSpec{Tolerations: []v1.Toleration{
+				{Key: "gpu", Operator: v1.TolerationOpEqual, Value: "true", Effect: v1.TaintEffectNoSchedule},
+				{Operator: v1.TolerationOpExists, Key: "node-role.kubernetes.io/control-plane"},
+			}},
+			count: 4,
+		},
+		{
+			name: "node affinity",
+			podSpec: v1.PodSpec{
+				Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
+				Affinity: &v1.Affinity{NodeAffinity: &v1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{
+						{MatchExpressions: []v1.NodeSelectorRequirement{
+							{Key: "role", Operator: v1.NodeSelectorOpIn, Values: []string{"worker"}},
+							{Key: "gpus", Operator: v1.NodeSelectorOpGt, Values: []string{"2"}},
+						}},
+						{MatchFields: []v1.NodeSelectorRequirement{
+							{Key: "metadata.name", Operator: v1.NodeSelectorOpIn, Values: []string{"control-plane-1"}},
+						}},
+					}},
+				}},
+			},
+			count: 2,
+		},
+		{
+			name: "empty node selector term",
+			podSpec: v1.PodSpec{Affinity: &v1.Affinity{NodeAffinity: &v1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{}}},
+			}}},
+			count: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+
+			r.Equal(test.count, daemonSetNodeCount(&test.podSpec, nodes(r), 10))
+		})
+	}
+}
+
+func TestDaemonSetNodes(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, normalDaemonSet)
+
+	objects[0].NodeCount = 3
+
+	usage, err := ResourceQuotaFromYaml(objects[0])
+	r.NoError(err)
+	r.Equal(int32(3), usage.Details.Replicas)
+	AssertEqualQuantities(r, resource.MustParse("1500m"), *usage.NormalResources.Requests.Cpu(), "cpu request value")
+
+	// the nodes take precedence over the node count
+	objects[0].Nodes = nodes(r)
+
+	usage, err = ResourceQuotaFromYaml(objects[0])
+	r.NoError(err)
+	r.Equal(int32(2), usage.Details.Replicas)
+	AssertEqualQuantities(r, resource.MustParse("1"), *usage.NormalResources.Requests.Cpu(), "cpu request value")
+}
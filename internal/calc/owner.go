@@ -0,0 +1,289 @@
+package calc
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ownerGraph links every object to its owners of the input, given by the metadata.ownerReferences.
+type ownerGraph struct {
+	objects []ResourceObject
+	// owners contains the indices of the owners of each object
+	owners [][]int
+}
+
+// newOwnerGraph builds the owner graph of the objects. Owners are matched by uid, or by group, kind, name and
+// namespace if the uid is not set or not part of the objects. Only owners in the namespace of the object are
+// linked. PersistentVolumeClaims created from the volumeClaimTemplates of a StatefulSet are linked to it by name.
+func newOwnerGraph(objects []ResourceObject) ownerGraph {
+	claimOwners := claimTemplateOwners(objects)
+	byUID := map[string]int{}
+	byName := map[string]int{}
+	namespaces := make([]string, len(objects))
+
+	for i, obj := range objects {
+		accessor, err := meta.Accessor(obj.Object)
+		if err != nil {
+			continue
+		}
+
+		namespaces[i] = accessor.GetNamespace()
+
+		if accessor.GetUID() != "" {
+			byUID[string(accessor.GetUID())] = i
+		}
+
+		byName[ownerKey(obj.Object.GetObjectKind().GroupVersionKind().GroupKind(), accessor.GetNamespace(), accessor.GetName())] = i
+	}
+
+	graph := ownerGraph{objects: objects, owners: make([][]int, len(objects))}
+
+	for i, obj := range objects {
+		accessor, err := meta.Accessor(obj.Object)
+		if err != nil {
+			continue
+		}
+
+		for _, ref := range accessor.GetOwnerReferences() {
+			owner, ok := byUID[string(ref.UID)]
+			if !ok {
+				gk := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind()
+				owner, ok = byName[ownerKey(gk, accessor.GetNamespace(), ref.Name)]
+			}
+
+			// cluster scoped owners, like the Node of a mirror Pod, do not calculate the pods of a namespace
+			if ok && owner != i && namespaces[owner] == accessor.GetNamespace() {
+				graph.owners[i] = append(graph.owners[i], owner)
+			}
+		}
+
+		if owner, ok := claimTemplateOwner(claimOwners, obj); ok && !slices.Contains(graph.owners[i], owner) {
+			graph.owners[i] = append(graph.owners[i], owner)
+		}
+	}
+
+	return graph
+}
+
+// claimTemplateOwners returns the indices of the StatefulSets by the name prefix of the PersistentVolumeClaims of
+// their volumeClaimTemplates, which are named <template>-<statefulset>-<ordinal> and have no owner reference unless
+// a persistentVolumeClaimRetentionPolicy deletes them.
+func claimTemplateOwners(objects []ResourceObject) map[string]int {
+	owners := map[string]int{}
+
+	for i, obj := range objects {
+		statefulSet, ok := obj.Object.(*appsv1.StatefulSet)
+		if !ok {
+			continue
+		}
+
+		for _, template := range statefulSet.Spec.VolumeClaimTemplates {
+			owners[statefulSet.Namespace+"/"+template.Name+"-"+statefulSet.Name] = i
+		}
+	}
+
+	return owners
+}
+
+// claimTemplateOwner returns the index of the StatefulSet the PersistentVolumeClaim was created for, if any.
+func claimTemplateOwner(owners map[string]int, obj ResourceObject) (int, bool) {
+	claim, ok := obj.Object.(*v1.PersistentVolumeClaim)
+	if !ok {
+		return 0, false
+	}
+
+	separator := strings.LastIndex(claim.Name, "-")
+	if separator < 0 {
+		return 0, false
+	}
+
+	if _, err := strconv.ParseUint(claim.Name[separator+1:], 10, 32); err != nil {
+		return 0, false
+	}
+
+	owner, ok := owners[claim.Namespace+"/"+claim.Name[:separator]]
+
+	return owner, ok
+}
+
+func ownerKey(gk schema.GroupKind, namespace, name string) string {
+	return gk.String() + "/" + namespace + "/" + name
+}
+
+// calculatedOwner returns the index of the nearest owner of the object that is calculated, directly or through
+// owners that are not, like the operator resource owning a StatefulSet. Objects without such an owner are not
+// calculated with any other object of the input.
+func (g ownerGraph) calculatedOwner(i int) (int, bool) {
+	visited := map[int]bool{i: true}
+	queue := slices.Clone(g.owners[i])
+
+	for len(queue) > 0 {
+		owner := queue[0]
+		queue = queue[1:]
+
+		if visited[owner] {
+			continue
+		}
+
+		visited[owner] = true
+
+		if isCalculated(g.objects[owner].Object) {
+			return owner, true
+		}
+
+		queue = append(queue, g.owners[owner]...)
+	}
+
+	return 0, false
+}
+
+// ancestorsOfLiveObjects returns the indices of all objects owning a Pod or PersistentVolumeClaim of the input,
+// directly or through other owners (e.g. the ReplicaSet and Deployment of a Pod or the StatefulSet of a claim).
+func (g ownerGraph) ancestorsOfLiveObjects() map[int]bool {
+	ancestors := map[int]bool{}
+
+	var mark func(i int)
+	mark = func(i int) {
+		for _, owner := range g.owners[i] {
+			if !ancestors[owner] {
+				ancestors[owner] = true
+				mark(owner)
+			}
+		}
+	}
+
+	for i, obj := range g.objects {
+		switch obj.Object.(type) {
+		case *v1.Pod, *v1.PersistentVolumeClaim:
+			mark(i)
+		}
+	}
+
+	return ancestors
+}
+
+// missingClaims returns the PersistentVolumeClaims the volumeClaimTemplates of a StatefulSet create for its live
+// Pods, named <template>-<pod>, by the index of the StatefulSet. Only StatefulSets without any claim in the input are
+// returned, like in the output of kubectl get all, so their storage is still calculated if they are removed for their
+// live pods.
+func (g ownerGraph) missingClaims() map[int][]ResourceObject {
+	claimed := map[int]bool{}
+	pods := map[int][]*v1.Pod{}
+
+	for i, obj := range g.objects {
+		switch obj := obj.Object.(type) {
+		case *v1.PersistentVolumeClaim:
+			for _, owner := range g.owners[i] {
+				claimed[owner] = true
+			}
+		case *v1.Pod:
+			if terminated(obj) {
+				continue
+			}
+
+			for _, owner := range g.owners[i] {
+				pods[owner] = append(pods[owner], obj)
+			}
+		}
+	}
+
+	claims := map[int][]ResourceObject{}
+
+	for owner, ownerPods := range pods {
+		statefulSet, ok := g.objects[owner].Object.(*appsv1.StatefulSet)
+		if !ok || claimed[owner] {
+			continue
+		}
+
+		for _, pod := range ownerPods {
+			for _, template := range statefulSet.Spec.VolumeClaimTemplates {
+				claim := &v1.PersistentVolumeClaim{
+					TypeMeta:   metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: "PersistentVolumeClaim"},
+					ObjectMeta: metav1.ObjectMeta{Name: template.Name + "-" + pod.Name, Namespace: statefulSet.Namespace},
+					Spec:       *template.Spec.DeepCopy(),
+				}
+
+				claims[owner] = append(claims[owner], ResourceObject{
+					Object:  claim,
+					Kind:    claim.Kind,
+					Version: v1.SchemeGroupVersion.Version,
+					Source:  g.objects[owner].Source,
+				})
+			}
+		}
+	}
+
+	return claims
+}
+
+// terminated reports whether the pod is in phase Succeeded or Failed and therefore does not use any resources.
+func terminated(pod *v1.Pod) bool {
+	return pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed
+}
+
+// DeduplicateOwned removes the objects whose pods are already calculated with other objects of the input, as in a
+// dump of a namespace (e.g. kubectl get all -o yaml). By default, only top-level controllers are kept and every
+// object owned by another calculated object of the input is removed, like the ReplicaSets of a Deployment, the Jobs
+// of a CronJob and their Pods or the PersistentVolumeClaims of a StatefulSet. Objects owned by resources kuota-calc
+// does not calculate, like the StatefulSet of an operator resource, are kept. If livePods is set, the Pods and
+// PersistentVolumeClaims of the input are kept instead and all their owners are removed, except for Pods in phase
+// Succeeded or Failed, which do not use any resources. A removed StatefulSet is replaced by the claims of its
+// volumeClaimTemplates for its live pods if none of its claims are part of the input. The returned messages describe
+// the removed objects.
+func DeduplicateOwned(objects []ResourceObject, livePods bool) ([]ResourceObject, []string) {
+	graph := newOwnerGraph(objects)
+
+	var (
+		ancestors map[int]bool
+		claims    map[int][]ResourceObject
+	)
+
+	if livePods {
+		ancestors = graph.ancestorsOfLiveObjects()
+		claims = graph.missingClaims()
+	}
+
+	kept := []ResourceObject{}
+	messages := []string{}
+
+	for i, obj := range objects {
+		pod, isPod := obj.Object.(*v1.Pod)
+		_, isClaim := obj.Object.(*v1.PersistentVolumeClaim)
+		owner, owned := graph.calculatedOwner(i)
+
+		switch {
+		case livePods && isPod && terminated(pod):
+			messages = append(messages, fmt.Sprintf("%s %s is terminated (%s)", obj.Kind, objectName(obj), pod.Status.Phase))
+		case livePods && (isPod || isClaim):
+			kept = append(kept, obj)
+		case ancestors[i]:
+			messages = append(messages, fmt.Sprintf("%s %s is calculated with its live pods", obj.Kind, objectName(obj)))
+			kept = append(kept, claims[i]...)
+		case owned:
+			messages = append(messages, fmt.Sprintf("%s %s is calculated with its owner %s %s",
+				obj.Kind, objectName(obj), objects[owner].Kind, objectName(objects[owner])))
+		default:
+			kept = append(kept, obj)
+		}
+	}
+
+	return kept, messages
+}
+
+// objectName returns the name of the object, or an empty string if it has no metadata.
+func objectName(obj ResourceObject) string {
+	accessor, err := meta.Accessor(obj.Object)
+	if err != nil {
+		return ""
+	}
+
+	return accessor.GetName()
+}
@@ -0,0 +1,185 @@
+package calc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestDeduplicateOwned(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, normalDeployment, deploymentReplicaSet, deploymentPod, normalReplicaSet)
+
+	kept, messages := DeduplicateOwned(objects, false)
+	r.Equal([]ResourceObject{objects[0], objects[3]}, kept)
+	r.Equal([]string{
+		"ReplicaSet normal-5d4f8c7b9 is calculated with its owner Deployment normal",
+		"Pod normal-5d4f8c7b9-x2x7k is calculated with its owner ReplicaSet normal-5d4f8c7b9",
+	}, messages)
+
+	kept, messages = DeduplicateOwned(objects, true)
+	r.Equal([]ResourceObject{objects[2], objects[3]}, kept)
+	r.Equal([]string{
+		"Deployment normal is calculated with its live pods",
+		"ReplicaSet normal-5d4f8c7b9 is calculated with its live pods",
+	}, messages)
+}
+
+func TestDeduplicateTerminatedPods(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, normalJob, succeededJobPod, failedJobPod, deploymentPod)
+
+	// terminated pods are removed together with their owner, which has no running pods
+	kept, messages := DeduplicateOwned(objects, true)
+	r.Equal([]ResourceObject{objects[3]}, kept)
+	r.Equal([]string{
+		"Job pi is calculated with its live pods",
+		"Pod pi-8xk2p is terminated (Succeeded)",
+		"Pod pi-4m9qz is terminated (Failed)",
+	}, messages)
+
+	// without live pods, the job is calculated instead
+	kept, _ = DeduplicateOwned(objects, false)
+	r.Equal([]ResourceObject{objects[0], objects[3]}, kept)
+}
+
+func TestDeduplicateStatefulSetClaims(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, volumeClaimTemplatesStatefulSet, statefulSetClaim, statefulSetLogsClaim, backupClaim,
+		otherNamespaceStatefulSetClaim)
+
+	// the claims of the volumeClaimTemplates are calculated with the StatefulSet, other claims are kept
+	kept, messages := DeduplicateOwned(objects, false)
+	r.Equal([]ResourceObject{objects[0], objects[3], objects[4]}, kept)
+	r.Equal([]string{
+		"PersistentVolumeClaim data-myapp-0 is calculated with its owner StatefulSet myapp",
+		"PersistentVolumeClaim logs-myapp-1 is calculated with its owner StatefulSet myapp",
+	}, messages)
+
+	kept, messages = DeduplicateOwned(objects, true)
+	r.Equal(objects[1:], kept)
+	r.Equal([]string{"StatefulSet myapp is calculated with its live pods"}, messages)
+}
+
+func TestDeduplicateStatefulSetWithoutClaims(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, volumeClaimTemplatesStatefulSet, statefulSetPod, secondStatefulSetPod)
+
+	// without claims in the input, the claims of the volumeClaimTemplates are calculated for the live pods
+	kept, messages := DeduplicateOwned(objects, true)
+	r.Equal([]string{"StatefulSet myapp is calculated with its live pods"}, messages)
+	r.Len(kept, 6)
+	r.Equal([]string{"data-myapp-0", "logs-myapp-0", "data-myapp-1", "logs-myapp-1", "myapp-0", "myapp-1"}, objectNames(kept))
+
+	usage := []*ResourceUsage{}
+
+	for _, obj := range kept {
+		u, err := ResourceQuotaFromYaml(obj)
+		r.NoError(err)
+
+		usage = append(usage, u)
+	}
+
+	storage := TotalStorage(usage)
+	AssertEqualQuantities(r, resource.MustParse("22Gi"), storage[v1.ResourceRequestsStorage], "storage request value")
+	AssertEqualQuantities(r, resource.MustParse("20Gi"), storage["gold.storageclass.storage.k8s.io/requests.storage"],
+		"gold storage request value")
+	AssertEqualQuantities(r, resource.MustParse("4"), storage[v1.ResourcePersistentVolumeClaims], "claims value")
+
+	// claims in the input replace the ones of the volumeClaimTemplates
+	kept, _ = DeduplicateOwned(append(objects, decodeObjects(r, statefulSetClaim)...), true)
+	r.Equal([]string{"myapp-0", "myapp-1", "data-myapp-0"}, objectNames(kept))
+}
+
+func objectNames(objects []ResourceObject) []string {
+	names := []string{}
+	for _, obj := range objects {
+		names = append(names, objectName(obj))
+	}
+
+	return names
+}
+
+func TestDeduplicateUncalculatedOwner(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, prometheus, prometheusStatefulSet, prometheusPod)
+
+	// the Prometheus is not calculated, so the StatefulSet it owns is kept
+	kept, messages := DeduplicateOwned(objects, false)
+	r.Equal([]ResourceObject{objects[0], objects[1]}, kept)
+	r.Equal([]string{"Pod prometheus-k8s-0 is calculated with its owner StatefulSet prometheus-k8s"}, messages)
+
+	_, err := ResourceQuotaFromYaml(kept[0])
+	r.ErrorIs(err, ErrResourceNotSupported)
+
+	usage, err := ResourceQuotaFromYaml(kept[1])
+	r.NoError(err)
+	AssertEqualQuantities(r, resource.MustParse("1"), *usage.NormalResources.Requests.Cpu(), "cpu request value")
+
+	// the nearest calculated owner is found through owners that are not calculated, here a Pod owned by a
+	// Prometheus owned by a StatefulSet
+	graph := newOwnerGraph(decodeObjects(r, prometheusStatefulSet, prometheus, prometheusPod))
+	graph.owners = [][]int{{1}, {0}, {1}}
+
+	owner, ok := graph.calculatedOwner(2)
+	r.True(ok)
+	r.Equal(0, owner)
+
+	// the StatefulSet is owned by the Prometheus only, the cycle back to itself is not followed
+	_, ok = graph.calculatedOwner(0)
+	r.False(ok)
+
+	kept, messages = DeduplicateOwned(objects, true)
+	r.Equal([]ResourceObject{objects[2]}, kept)
+	r.Equal([]string{
+		"Prometheus k8s is calculated with its live pods",
+		"StatefulSet prometheus-k8s is calculated with its live pods",
+	}, messages)
+}
+
+func TestIsCalculated(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, normalDeployment, normalDeploymentConfig, normalStatefulSet, normalReplicaSet,
+		normalReplicationController, normalDaemonSet, normalJob, normalCronJob, normalPod, normalPersistentVolumeClaim,
+		normalScaledJob, blueGreenRollout, normalKnativeService, normalScaledObject, normalConfigMap, service, prometheus)
+
+	for _, obj := range objects {
+		_, err := ResourceQuotaFromYaml(obj)
+		r.Equal(!errors.Is(err, ErrResourceNotSupported), isCalculated(obj.Object), "%s %s", obj.Kind, objectName(obj))
+	}
+}
+
+func TestOwnerGraph(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, normalDeployment, deploymentReplicaSet, deploymentPod)
+
+	// a different uid of the owner reference is matched by name
+	replicaSet, err := meta.Accessor(objects[1].Object)
+	r.NoError(err)
+
+	refs := replicaSet.GetOwnerReferences()
+	refs[0].UID = types.UID("deployment-uid")
+	replicaSet.SetOwnerReferences(refs)
+
+	graph := newOwnerGraph(objects)
+	r.Equal([][]int{nil, {0}, {1}}, graph.owners)
+	r.Equal(map[int]bool{0: true, 1: true}, graph.ancestorsOfLiveObjects())
+
+	// owners of other namespaces are not linked
+	replicaSet.SetNamespace("other")
+
+	graph = newOwnerGraph(objects)
+	r.Equal([][]int{nil, nil, nil}, graph.owners)
+}
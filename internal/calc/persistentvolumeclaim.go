@@ -0,0 +1,61 @@
+package calc
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// storageClassSuffix is appended to a storage class name to build the per storage class ResourceQuota names,
+// e.g. gold.storageclass.storage.k8s.io/requests.storage.
+const storageClassSuffix = ".storageclass.storage.k8s.io/"
+
+func persistentVolumeClaim(pvc v1.PersistentVolumeClaim) *ResourceUsage {
+	resourceUsage := ResourceUsage{
+		NormalResources:  Resources{},
+		RolloutResources: Resources{},
+		Storage:          claimStorage(&pvc.Spec),
+		Details: Details{
+			Version:     pvc.APIVersion,
+			Kind:        pvc.Kind,
+			Name:        pvc.Name,
+			Namespace:   pvc.Namespace,
+			Strategy:    "",
+			Replicas:    0,
+			MaxReplicas: 0,
+		},
+	}
+
+	return &resourceUsage
+}
+
+// claimStorage returns the storage quota usage of a single claim. The requested storage and the claim itself are
+// counted in total and, if the claim has a storage class, for the storage class.
+func claimStorage(spec *v1.PersistentVolumeClaimSpec) v1.ResourceList {
+	request := spec.Resources.Requests.Storage().DeepCopy()
+	claims := *resource.NewQuantity(1, resource.DecimalSI)
+
+	storage := v1.ResourceList{
+		v1.ResourceRequestsStorage:        request,
+		v1.ResourcePersistentVolumeClaims: claims,
+	}
+
+	if spec.StorageClassName != nil && *spec.StorageClassName != "" {
+		prefix := v1.ResourceName(*spec.StorageClassName + storageClassSuffix)
+
+		storage[prefix+v1.ResourceRequestsStorage] = request.DeepCopy()
+		storage[prefix+v1.ResourcePersistentVolumeClaims] = claims.DeepCopy()
+	}
+
+	return storage
+}
+
+// TotalStorage calculates the sum of the storage quota usage of all usages.
+func TotalStorage(usage []*ResourceUsage) v1.ResourceList {
+	total := v1.ResourceList{}
+
+	for _, u := range usage {
+		total = addResourceList(total, u.Storage)
+	}
+
+	return total
+}
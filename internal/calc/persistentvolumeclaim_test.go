@@ -0,0 +1,53 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPersistentVolumeClaim(t *testing.T) {
+	r := require.New(t)
+
+	resourceObject, kind, version, _ := ConvertToRuntimeObjectFromYaml([]byte(normalPersistentVolumeClaim), false)
+
+	usage, err := ResourceQuotaFromYaml(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version})
+	r.NoError(err)
+	r.Equal("PersistentVolumeClaim", usage.Details.Kind)
+	r.Empty(usage.RolloutResources.Requests)
+
+	r.Len(usage.Storage, 4)
+	AssertEqualQuantities(r, resource.MustParse("100Gi"), usage.Storage[v1.ResourceRequestsStorage], "storage request value")
+	AssertEqualQuantities(r, resource.MustParse("1"), usage.Storage[v1.ResourcePersistentVolumeClaims], "claims value")
+	AssertEqualQuantities(r, resource.MustParse("100Gi"), usage.Storage["silver.storageclass.storage.k8s.io/requests.storage"], "silver storage request value")
+}
+
+func TestTotalStorage(t *testing.T) {
+	r := require.New(t)
+
+	objects := []ResourceObject{}
+	usage := []*ResourceUsage{}
+
+	for _, document := range []string{volumeClaimTemplatesStatefulSet, normalPersistentVolumeClaim, normalDeployment} {
+		resourceObject, kind, version, _ := ConvertToRuntimeObjectFromYaml([]byte(document), false)
+
+		object := ResourceObject{Object: resourceObject, Kind: *kind, Version: *version}
+
+		u, err := ResourceQuotaFromYaml(object)
+		r.NoError(err)
+
+		objects = append(objects, object)
+		usage = append(usage, u)
+	}
+
+	total := TotalStorage(usage)
+	r.Len(total, 6)
+	AssertEqualQuantities(r, resource.MustParse("133Gi"), total[v1.ResourceRequestsStorage], "storage request value")
+	AssertEqualQuantities(r, resource.MustParse("7"), total[v1.ResourcePersistentVolumeClaims], "claims value")
+
+	hard := QuotaUsage(-1, objects, usage)
+	AssertEqualQuantities(r, resource.MustParse("133Gi"), hard[v1.ResourceRequestsStorage], "quota storage request value")
+	r.Contains(hard, v1.ResourceRequestsCPU)
+}
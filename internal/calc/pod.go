@@ -1,6 +1,23 @@
 package calc
 
-import v1 "k8s.io/api/core/v1"
+import (
+	openshiftAppsV1 "github.com/openshift/api/apps/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchV1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// unstructuredPodSpecFields are the fields of the pod spec of the unstructured kinds creating pods.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var unstructuredPodSpecFields = map[schema.GroupKind][]string{
+	scaledJobGroupKind:      {"spec", "jobTargetRef", "template", "spec"},
+	rolloutGroupKind:        {"spec", "template", "spec"},
+	knativeServiceGroupKind: {"spec", "template", "spec"},
+}
 
 func pod(pod v1.Pod) *ResourceUsage {
 	podResources := calcPodResources(&pod.Spec)
@@ -9,14 +26,110 @@ func pod(pod v1.Pod) *ResourceUsage {
 		NormalResources:  podResources.Containers,
 		RolloutResources: podResources.MaxResources,
 		Details: Details{
-			Version:     pod.APIVersion,
-			Kind:        pod.Kind,
-			Name:        pod.Name,
-			Strategy:    "",
-			Replicas:    0,
-			MaxReplicas: 0,
+			Version:       pod.APIVersion,
+			Kind:          pod.Kind,
+			Name:          pod.Name,
+			Namespace:     pod.Namespace,
+			ResourcesFrom: podResources.ResourcesFrom(),
+			Strategy:      "",
+			Replicas:      0,
+			MaxReplicas:   0,
 		},
 	}
 
 	return &resourceUsage
 }
+
+// podSpec returns the spec of the pods created by the object, or nil if the object does not create pods.
+func podSpec(obj runtime.Object) *v1.PodSpec {
+	switch obj := obj.(type) {
+	case *openshiftAppsV1.DeploymentConfig:
+		if obj.Spec.Template == nil {
+			return nil
+		}
+
+		return &obj.Spec.Template.Spec
+	case *appsv1.Deployment:
+		return &obj.Spec.Template.Spec
+	case *appsv1.StatefulSet:
+		return &obj.Spec.Template.Spec
+	case *appsv1.DaemonSet:
+		return &obj.Spec.Template.Spec
+	case *appsv1.ReplicaSet:
+		return &obj.Spec.Template.Spec
+	case *v1.ReplicationController:
+		if obj.Spec.Template == nil {
+			return nil
+		}
+
+		return &obj.Spec.Template.Spec
+	case *batchV1.Job:
+		return &obj.Spec.Template.Spec
+	case *batchV1.CronJob:
+		return &obj.Spec.JobTemplate.Spec.Template.Spec
+	case *v1.Pod:
+		return &obj.Spec
+	default:
+		return nil
+	}
+}
+
+// updatePodSpecs calls update with the pod spec of a copy of the object and of its LinkedWorkload, which contains
+// the pod template of an Argo Rollout with a workloadRef. It returns false if neither of them creates pods.
+func updatePodSpecs(resourceObject ResourceObject, update func(spec *v1.PodSpec)) (ResourceObject, bool) {
+	obj, updated := updatePodSpec(resourceObject.Object, update)
+	resourceObject.Object = obj
+
+	if resourceObject.LinkedWorkload != nil {
+		workload, ok := updatePodSpec(resourceObject.LinkedWorkload, update)
+		resourceObject.LinkedWorkload = workload
+		updated = updated || ok
+	}
+
+	return resourceObject, updated
+}
+
+// updatePodSpec calls update with the pod spec of a copy of the object and returns the copy, or false if the object
+// does not create pods. The pod spec of an unstructured object is converted and written back to the copy.
+func updatePodSpec(obj runtime.Object, update func(spec *v1.PodSpec)) (runtime.Object, bool) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		if podSpec(obj) == nil {
+			return obj, false
+		}
+
+		obj = obj.DeepCopyObject()
+		update(podSpec(obj))
+
+		return obj, true
+	}
+
+	fields, ok := unstructuredPodSpecFields[u.GroupVersionKind().GroupKind()]
+	if !ok {
+		return obj, false
+	}
+
+	specMap, found, err := unstructured.NestedMap(u.Object, fields...)
+	if err != nil || !found {
+		return obj, false
+	}
+
+	var spec v1.PodSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(specMap, &spec); err != nil {
+		return obj, false
+	}
+
+	update(&spec)
+
+	specMap, err = runtime.DefaultUnstructuredConverter.ToUnstructured(&spec)
+	if err != nil {
+		return obj, false
+	}
+
+	u = u.DeepCopy()
+	if err := unstructured.SetNestedMap(u.Object, specMap, fields...); err != nil {
+		return obj, false
+	}
+
+	return u, true
+}
@@ -5,7 +5,10 @@ import (
 
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestPod(t *testing.T) {
@@ -70,14 +73,14 @@ func TestPod(t *testing.T) {
 
 				resourceObject, kind, version, _ := ConvertToRuntimeObjectFromYaml([]byte(test.pod), false)
 
-				usage, err := ResourceQuotaFromYaml(ResourceObject{resourceObject, *kind, *version, nil})
+				usage, err := ResourceQuotaFromYaml(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version})
 				r.NoError(err)
 				r.NotEmpty(usage)
 
-				AssertEqualQuantities(r, test.cpuMin, usage.RolloutResources.CPUMin, "cpu request value")
-				AssertEqualQuantities(r, test.cpuMax, usage.RolloutResources.CPUMax, "cpu limit value")
-				AssertEqualQuantities(r, test.memoryMin, usage.RolloutResources.MemoryMin, "memory request value")
-				AssertEqualQuantities(r, test.memoryMax, usage.RolloutResources.MemoryMax, "memory limit value")
+				AssertEqualQuantities(r, test.cpuMin, *usage.RolloutResources.Requests.Cpu(), "cpu request value")
+				AssertEqualQuantities(r, test.cpuMax, *usage.RolloutResources.Limits.Cpu(), "cpu limit value")
+				AssertEqualQuantities(r, test.memoryMin, *usage.RolloutResources.Requests.Memory(), "memory request value")
+				AssertEqualQuantities(r, test.memoryMax, *usage.RolloutResources.Limits.Memory(), "memory limit value")
 				r.Equalf(test.replicas, usage.Details.Replicas, "replicas")
 				r.Equalf(test.maxReplicas, usage.Details.MaxReplicas, "maxReplicas")
 				r.Equalf(string(test.strategy), usage.Details.Strategy, "strategy")
@@ -85,3 +88,157 @@ func TestPod(t *testing.T) {
 		)
 	}
 }
+
+func TestPodExtendedResources(t *testing.T) {
+	r := require.New(t)
+
+	resourceObject, kind, version, _ := ConvertToRuntimeObjectFromYaml([]byte(extendedResourcesPod), false)
+
+	usage, err := ResourceQuotaFromYaml(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version})
+	r.NoError(err)
+
+	requests := usage.RolloutResources.Requests
+	limits := usage.RolloutResources.Limits
+
+	AssertEqualQuantities(r, resource.MustParse("2Gi"), *requests.StorageEphemeral(), "ephemeral-storage request value")
+	AssertEqualQuantities(r, resource.MustParse("4Gi"), *limits.StorageEphemeral(), "ephemeral-storage limit value")
+	AssertEqualQuantities(r, resource.MustParse("100Mi"), requests["hugepages-2Mi"], "hugepages request value")
+	AssertEqualQuantities(r, resource.MustParse("1"), requests["nvidia.com/gpu"], "gpu request value")
+	AssertEqualQuantities(r, resource.MustParse("1"), limits["nvidia.com/gpu"], "gpu limit value")
+	AssertEqualQuantities(r, resource.MustParse("500Mi"), *usage.NormalResources.Requests.StorageEphemeral(), "normal ephemeral-storage request value")
+}
+
+func TestPodSidecars(t *testing.T) {
+	r := require.New(t)
+
+	resourceObject, kind, version, _ := ConvertToRuntimeObjectFromYaml([]byte(sidecarPod), false)
+
+	usage, err := ResourceQuotaFromYaml(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version})
+	r.NoError(err)
+
+	// the sidecar runs next to the app container
+	AssertEqualQuantities(r, resource.MustParse("600m"), *usage.NormalResources.Requests.Cpu(), "cpu request value")
+	AssertEqualQuantities(r, resource.MustParse("1500m"), *usage.NormalResources.Limits.Cpu(), "cpu limit value")
+	AssertEqualQuantities(r, resource.MustParse("384Mi"), *usage.NormalResources.Requests.Memory(), "memory request value")
+	AssertEqualQuantities(r, resource.MustParse("768Mi"), *usage.NormalResources.Limits.Memory(), "memory limit value")
+
+	// the setup init container runs next to the sidecar started before it
+	AssertEqualQuantities(r, resource.MustParse("2050m"), *usage.RolloutResources.Requests.Cpu(), "rollout cpu request value")
+	AssertEqualQuantities(r, resource.MustParse("2450m"), *usage.RolloutResources.Limits.Cpu(), "rollout cpu limit value")
+	AssertEqualQuantities(r, resource.MustParse("1Gi"), *usage.RolloutResources.Requests.Memory(), "rollout memory request value")
+	AssertEqualQuantities(r, resource.MustParse("1Gi"), *usage.RolloutResources.Limits.Memory(), "rollout memory limit value")
+}
+
+func TestPodLevelResources(t *testing.T) {
+	r := require.New(t)
+
+	resourceObject, kind, version, _ := ConvertToRuntimeObjectFromYaml([]byte(podLevelResourcesPod), false)
+
+	usage, err := ResourceQuotaFromYaml(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version})
+	r.NoError(err)
+	r.Equal("pod (cpu, memory), containers", usage.Details.ResourcesFrom)
+
+	AssertEqualQuantities(r, resource.MustParse("1"), *usage.RolloutResources.Requests.Cpu(), "cpu request value")
+	AssertEqualQuantities(r, resource.MustParse("2"), *usage.RolloutResources.Limits.Cpu(), "cpu limit value")
+	AssertEqualQuantities(r, resource.MustParse("2Gi"), *usage.RolloutResources.Requests.Memory(), "memory request value")
+	AssertEqualQuantities(r, resource.MustParse("2Gi"), *usage.RolloutResources.Limits.Memory(), "memory limit value")
+	AssertEqualQuantities(r, resource.MustParse("1Gi"), *usage.RolloutResources.Requests.StorageEphemeral(), "ephemeral-storage request value")
+
+	pod, ok := resourceObject.(*v1.Pod)
+	r.True(ok)
+
+	pod.Spec.Containers[0].Resources.Requests = v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")}
+	r.Equal("pod", calcPodResources(&pod.Spec).ResourcesFrom())
+
+	pod.Spec.Resources.Limits = nil
+	podResources := calcPodResources(&pod.Spec)
+	r.Equal("pod (cpu), containers", podResources.ResourcesFrom())
+	AssertEqualQuantities(r, resource.MustParse("1"), *podResources.Containers.Requests.Cpu(), "pod cpu request value")
+	AssertEqualQuantities(r, resource.MustParse("1"), *podResources.Containers.Limits.Cpu(), "container cpu limit value")
+	AssertEqualQuantities(r, resource.MustParse("512Mi"), *podResources.Containers.Limits.Memory(), "container memory limit value")
+
+	pod.Spec.Resources = nil
+	r.Equal("containers", calcPodResources(&pod.Spec).ResourcesFrom())
+}
+
+// applyPodDefaults applies the kata RuntimeClass and the LimitRange defaults to the object, like in the cmd.
+func applyPodDefaults(r *require.Assertions, object ResourceObject) ResourceObject {
+	_, lr := limitRangeObjects(r, "")
+
+	runtimeClassObject, _, _, err := ConvertToRuntimeObjectFromYaml([]byte(kataRuntimeClass), false)
+	r.NoError(err)
+
+	runtimeClass, ok := runtimeClassObject.(*nodev1.RuntimeClass)
+	r.True(ok)
+
+	object, _ = ApplyLimitRanges(ApplyRuntimeClasses(object, []*nodev1.RuntimeClass{runtimeClass}), []*v1.LimitRange{lr})
+
+	return object
+}
+
+func TestUnstructuredPodDefaults(t *testing.T) {
+	var tests = []struct {
+		name       string
+		documents  []string
+		cpuRequest resource.Quantity
+		// originalCPURequest is the cpu request without the LimitRange defaults and the RuntimeClass overhead
+		originalCPURequest resource.Quantity
+	}{
+		{
+			name:       "ScaledJob",
+			documents:  []string{defaultsScaledJob},
+			cpuRequest: resource.MustParse("1"),
+		},
+		{
+			name:       "Rollout",
+			documents:  []string{defaultsRollout},
+			cpuRequest: resource.MustParse("1"),
+		},
+		{
+			name:       "Rollout with workloadRef",
+			documents:  []string{defaultsWorkloadRefRollout, defaultsDeployment},
+			cpuRequest: resource.MustParse("1"),
+		},
+		{
+			// the queue-proxy sidecar requests 25m cpu
+			name:               "Knative Service",
+			documents:          []string{noResourcesKnativeService},
+			cpuRequest:         resource.MustParse("1050m"),
+			originalCPURequest: resource.MustParse("50m"),
+		},
+	}
+
+	kinds := map[schema.GroupKind]bool{}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+
+			objects := decodeObjects(r, test.documents...)
+			r.Empty(LinkWorkloadRefs(objects))
+
+			kinds[objects[0].Object.GetObjectKind().GroupVersionKind().GroupKind()] = true
+
+			usage, err := ResourceQuotaFromYaml(applyPodDefaults(r, objects[0]))
+			r.NoError(err)
+			r.Equal(int32(2), usage.Details.Replicas)
+
+			// LimitRange defaults and the RuntimeClass overhead of two pods
+			AssertEqualQuantities(r, test.cpuRequest, *usage.NormalResources.Requests.Cpu(), "cpu request value")
+			AssertEqualQuantities(r, resource.MustParse("1500m"), *usage.NormalResources.Limits.Cpu(), "cpu limit value")
+			AssertEqualQuantities(r, resource.MustParse("832Mi"), *usage.NormalResources.Requests.Memory(), "memory request value")
+			AssertEqualQuantities(r, resource.MustParse("1344Mi"), *usage.NormalResources.Limits.Memory(), "memory limit value")
+
+			// the original objects are left untouched
+			usage, err = ResourceQuotaFromYaml(objects[0])
+			r.NoError(err)
+			AssertEqualQuantities(r, test.originalCPURequest, *usage.NormalResources.Requests.Cpu(), "original cpu request value")
+		})
+	}
+
+	// every unstructured kind creating pods is covered
+	for gk := range unstructuredPodSpecFields {
+		r := require.New(t)
+		r.True(kinds[gk], "%s is not tested", gk)
+	}
+}
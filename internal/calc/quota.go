@@ -0,0 +1,64 @@
+package calc
+
+import (
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// QuotaResourceList converts the given resources to the resource names used by a ResourceQuota, e.g. requests.cpu
+// or limits.memory. Resources with a total of zero are left out, as a hard limit of zero rejects every pod in the
+// namespace, e.g. limits.cpu if no container has a cpu limit. Hugepages and extended resources only support quotas on
+// requests.
+func QuotaResourceList(r Resources) v1.ResourceList {
+	list := v1.ResourceList{}
+
+	for _, name := range r.ResourceNames() {
+		if q, ok := r.Requests[name]; ok && !q.IsZero() {
+			list[v1.DefaultResourceRequestsPrefix+name] = q.DeepCopy()
+		}
+
+		if q, ok := r.Limits[name]; ok && !q.IsZero() && supportsLimitQuota(name) {
+			list[v1.ResourceName("limits.")+name] = q.DeepCopy()
+		}
+	}
+
+	return list
+}
+
+// supportsLimitQuota reports whether a ResourceQuota can restrict the limits of a resource. This is the case for
+// native resources like ephemeral-storage, but not for hugepages or extended resources.
+func supportsLimitQuota(name v1.ResourceName) bool {
+	if strings.HasPrefix(string(name), v1.ResourceHugePagesPrefix) {
+		return false
+	}
+
+	return !strings.Contains(string(name), "/") || strings.Contains(string(name), "kubernetes.io/")
+}
+
+// QuotaUsage returns the total quota usage of all objects and their usages, the compute resources (see Total and
+// QuotaResourceList), the storage and the object counts.
+func QuotaUsage(maxRollout int, objects []ResourceObject, usage []*ResourceUsage) v1.ResourceList {
+	hard := addResourceList(QuotaResourceList(Total(maxRollout, usage)), TotalStorage(usage))
+
+	return addResourceList(hard, TotalObjectCount(objects, usage))
+}
+
+// ResourceQuota creates a ResourceQuota with the given name and namespace, whose hard limits are set to the
+// provided quota usage. The namespace is omitted if empty.
+func ResourceQuota(name, namespace string, hard v1.ResourceList) *v1.ResourceQuota {
+	return &v1.ResourceQuota{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       "ResourceQuota",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: v1.ResourceQuotaSpec{
+			Hard: hard,
+		},
+	}
+}
@@ -0,0 +1,72 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestResourceQuota(t *testing.T) {
+	r := require.New(t)
+
+	total := Resources{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("3250m"), v1.ResourceMemory: resource.MustParse("26Gi")},
+		Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("6500m"), v1.ResourceMemory: resource.MustParse("52Gi")},
+	}
+
+	quota := ResourceQuota("compute", "team-a", QuotaResourceList(total))
+
+	r.Equal("v1", quota.APIVersion)
+	r.Equal("ResourceQuota", quota.Kind)
+	r.Equal("compute", quota.Name)
+	r.Equal("team-a", quota.Namespace)
+	r.Len(quota.Spec.Hard, 4)
+	AssertEqualQuantities(r, *total.Requests.Cpu(), quota.Spec.Hard[v1.ResourceRequestsCPU], "cpu request value")
+	AssertEqualQuantities(r, *total.Limits.Cpu(), quota.Spec.Hard[v1.ResourceLimitsCPU], "cpu limit value")
+	AssertEqualQuantities(r, *total.Requests.Memory(), quota.Spec.Hard[v1.ResourceRequestsMemory], "memory request value")
+	AssertEqualQuantities(r, *total.Limits.Memory(), quota.Spec.Hard[v1.ResourceLimitsMemory], "memory limit value")
+}
+
+func TestQuotaResourceList(t *testing.T) {
+	r := require.New(t)
+
+	list := QuotaResourceList(Resources{
+		Requests: v1.ResourceList{
+			v1.ResourceEphemeralStorage: resource.MustParse("1Gi"),
+			"hugepages-2Mi":             resource.MustParse("100Mi"),
+			"nvidia.com/gpu":            resource.MustParse("2"),
+		},
+		Limits: v1.ResourceList{
+			v1.ResourceEphemeralStorage: resource.MustParse("2Gi"),
+			"hugepages-2Mi":             resource.MustParse("100Mi"),
+			"nvidia.com/gpu":            resource.MustParse("2"),
+		},
+	})
+
+	// cpu and memory are not set by any container, a hard limit of zero would reject every pod
+	r.Len(list, 4)
+	r.NotContains(list, v1.ResourceRequestsCPU)
+	r.NotContains(list, v1.ResourceLimitsMemory)
+	AssertEqualQuantities(r, resource.MustParse("1Gi"), list[v1.ResourceRequestsEphemeralStorage], "ephemeral-storage request value")
+	AssertEqualQuantities(r, resource.MustParse("2Gi"), list[v1.ResourceLimitsEphemeralStorage], "ephemeral-storage limit value")
+	AssertEqualQuantities(r, resource.MustParse("100Mi"), list["requests.hugepages-2Mi"], "hugepages request value")
+	AssertEqualQuantities(r, resource.MustParse("2"), list["requests.nvidia.com/gpu"], "gpu request value")
+	r.NotContains(list, v1.ResourceName("limits.hugepages-2Mi"))
+	r.NotContains(list, v1.ResourceName("limits.nvidia.com/gpu"))
+}
+
+func TestQuotaResourceListWithoutLimits(t *testing.T) {
+	r := require.New(t)
+
+	list := QuotaResourceList(Resources{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m"), v1.ResourceMemory: resource.MustParse("1Gi")},
+		Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("0"), v1.ResourceEphemeralStorage: resource.MustParse("0")},
+	})
+
+	r.Equal(v1.ResourceList{
+		v1.ResourceRequestsCPU:    resource.MustParse("500m"),
+		v1.ResourceRequestsMemory: resource.MustParse("1Gi"),
+	}, list)
+}
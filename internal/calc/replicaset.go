@@ -0,0 +1,76 @@
+package calc
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	v2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+)
+
+// replicaSet calculates the cpu/memory resources a bare ReplicaSet needs. A ReplicaSet does not replace its pods
+// on template changes, the most expensive case is recreating all pods at once, with the init containers being more
+// expensive than the normal containers.
+func replicaSet(rs appsv1.ReplicaSet, hpa *v2.HorizontalPodAutoscaler, hpaMode HpaMode) *ResourceUsage {
+	// https://github.com/kubernetes/api/blob/v0.32.3/apps/v1/types.go#L876
+	replicas := int32(1)
+	if rs.Spec.Replicas != nil {
+		replicas = *rs.Spec.Replicas
+	}
+
+	replicas, isHpa := hpaReplicas(replicas, hpa, hpaMode)
+	podResources := calcPodResources(&rs.Spec.Template.Spec)
+
+	resourceUsage := ResourceUsage{
+		NormalResources:  podResources.Containers.MulInt32(replicas),
+		RolloutResources: podResources.MaxResources.MulInt32(replicas),
+		Details: Details{
+			Version:       rs.APIVersion,
+			Kind:          rs.Kind,
+			Name:          rs.Name,
+			Namespace:     rs.Namespace,
+			ResourcesFrom: podResources.ResourcesFrom(),
+			Strategy:      "",
+			Replicas:      replicas,
+			MaxReplicas:   replicas,
+			Hpa:           isHpa,
+		},
+	}
+
+	return &resourceUsage
+}
+
+// replicationController calculates the cpu/memory resources a ReplicationController needs, just like a ReplicaSet.
+// A ReplicationController without a template does not create any pods.
+func replicationController(rc v1.ReplicationController, hpa *v2.HorizontalPodAutoscaler, hpaMode HpaMode) *ResourceUsage {
+	// https://github.com/kubernetes/api/blob/v0.32.3/core/v1/types.go#L5447
+	replicas := int32(1)
+	if rc.Spec.Replicas != nil {
+		replicas = *rc.Spec.Replicas
+	}
+
+	replicas, isHpa := hpaReplicas(replicas, hpa, hpaMode)
+
+	template := rc.Spec.Template
+	if template == nil {
+		template = &v1.PodTemplateSpec{}
+	}
+
+	podResources := calcPodResources(&template.Spec)
+
+	resourceUsage := ResourceUsage{
+		NormalResources:  podResources.Containers.MulInt32(replicas),
+		RolloutResources: podResources.MaxResources.MulInt32(replicas),
+		Details: Details{
+			Version:       rc.APIVersion,
+			Kind:          rc.Kind,
+			Name:          rc.Name,
+			Namespace:     rc.Namespace,
+			ResourcesFrom: podResources.ResourcesFrom(),
+			Strategy:      "",
+			Replicas:      replicas,
+			MaxReplicas:   replicas,
+			Hpa:           isHpa,
+		},
+	}
+
+	return &resourceUsage
+}
@@ -0,0 +1,70 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestReplicaSet(t *testing.T) {
+	var tests = []struct {
+		name          string
+		object        string
+		kind          string
+		replicas      int32
+		cpuMin        resource.Quantity
+		cpuMax        resource.Quantity
+		memoryMin     resource.Quantity
+		memoryMax     resource.Quantity
+		rolloutCPU    resource.Quantity
+		rolloutMemory resource.Quantity
+	}{
+		{
+			name:          "replicaset",
+			object:        normalReplicaSet,
+			kind:          "ReplicaSet",
+			replicas:      3,
+			cpuMin:        resource.MustParse("300m"),
+			cpuMax:        resource.MustParse("600m"),
+			memoryMin:     resource.MustParse("192Mi"),
+			memoryMax:     resource.MustParse("384Mi"),
+			rolloutCPU:    resource.MustParse("1500m"),
+			rolloutMemory: resource.MustParse("384Mi"),
+		},
+		{
+			name:          "replicationcontroller",
+			object:        normalReplicationController,
+			kind:          "ReplicationController",
+			replicas:      2,
+			cpuMin:        resource.MustParse("500m"),
+			cpuMax:        resource.MustParse("1"),
+			memoryMin:     resource.MustParse("256Mi"),
+			memoryMax:     resource.MustParse("512Mi"),
+			rolloutCPU:    resource.MustParse("500m"),
+			rolloutMemory: resource.MustParse("256Mi"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+
+			resourceObject, kind, version, err := ConvertToRuntimeObjectFromYaml([]byte(test.object), false)
+			r.NoError(err)
+
+			usage, err := ResourceQuotaFromYaml(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version})
+			r.NoError(err)
+			r.Equal(test.kind, usage.Details.Kind)
+			r.Equal(test.replicas, usage.Details.Replicas)
+			r.Equal(test.replicas, usage.Details.MaxReplicas)
+
+			AssertEqualQuantities(r, test.cpuMin, *usage.NormalResources.Requests.Cpu(), "cpu request value")
+			AssertEqualQuantities(r, test.cpuMax, *usage.NormalResources.Limits.Cpu(), "cpu limit value")
+			AssertEqualQuantities(r, test.memoryMin, *usage.NormalResources.Requests.Memory(), "memory request value")
+			AssertEqualQuantities(r, test.memoryMax, *usage.NormalResources.Limits.Memory(), "memory limit value")
+			AssertEqualQuantities(r, test.rolloutCPU, *usage.RolloutResources.Requests.Cpu(), "rollout cpu request value")
+			AssertEqualQuantities(r, test.rolloutMemory, *usage.RolloutResources.Requests.Memory(), "rollout memory request value")
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package calc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+//nolint:gochecknoglobals // read-only lookup table
+var rolloutGroupKind = schema.GroupKind{Group: "argoproj.io", Kind: "Rollout"}
+
+// rolloutSpec contains the fields of an Argo Rollout needed for the calculation,
+// see https://argo-rollouts.readthedocs.io/en/stable/features/specification/
+type rolloutSpec struct {
+	Replicas    *int32                          `json:"replicas"`
+	Template    v1.PodTemplateSpec              `json:"template"`
+	WorkloadRef *v2.CrossVersionObjectReference `json:"workloadRef"`
+	Strategy    struct {
+		BlueGreen *blueGreenStrategy `json:"blueGreen"`
+		Canary    *canaryStrategy    `json:"canary"`
+	} `json:"strategy"`
+}
+
+type blueGreenStrategy struct {
+	PreviewReplicaCount *int32 `json:"previewReplicaCount"`
+}
+
+type canaryStrategy struct {
+	MaxSurge           *intstr.IntOrString `json:"maxSurge"`
+	MaxUnavailable     *intstr.IntOrString `json:"maxUnavailable"`
+	Steps              []canaryStep        `json:"steps"`
+	TrafficRouting     map[string]any      `json:"trafficRouting"`
+	DynamicStableScale bool                `json:"dynamicStableScale"`
+}
+
+type canaryStep struct {
+	SetWeight      *int32 `json:"setWeight"`
+	SetCanaryScale *struct {
+		Replicas           *int32 `json:"replicas"`
+		Weight             *int32 `json:"weight"`
+		MatchTrafficWeight bool   `json:"matchTrafficWeight"`
+	} `json:"setCanaryScale"`
+}
+
+// rollout calculates the resources an Argo Rollout needs. The pod template is taken from the Deployment referenced
+// by workloadRef, if set. A Rollout whose Deployment is not part of the input is not supported. Replicas and the
+// blue-green or canary strategy are taken into account.
+func rollout(obj *unstructured.Unstructured, workloadRef runtime.Object, hpa *v2.HorizontalPodAutoscaler, hpaMode HpaMode) (*ResourceUsage, error) {
+	var spec rolloutSpec
+
+	if err := unstructuredSpec(obj, &spec); err != nil {
+		return nil, err
+	}
+
+	replicas := int32(1)
+	if spec.Replicas != nil {
+		replicas = *spec.Replicas
+	}
+
+	replicas, isHpa := hpaReplicas(replicas, hpa, hpaMode)
+
+	template := &spec.Template
+
+	if spec.WorkloadRef != nil {
+		// LinkWorkloadRefs warns about a missing workloadRef, the Rollout is skipped like an unsupported object then
+		deployment, ok := workloadRef.(*appsv1.Deployment)
+		if !ok {
+			return nil, fmt.Errorf("%w: rollout %s: workloadRef %s %s not found",
+				ErrResourceNotSupported, obj.GetName(), spec.WorkloadRef.Kind, spec.WorkloadRef.Name)
+		}
+
+		template = &deployment.Spec.Template
+	}
+
+	var (
+		strategy            string
+		maxReplicas         int32 // max amount of pods of the stable and the new ReplicaSet during a rollout
+		maxNonReadyPodCount int32 // max pods of the new ReplicaSet that are not ready during a rollout
+		err                 error
+	)
+
+	switch {
+	case spec.Strategy.BlueGreen != nil:
+		strategy = "BlueGreen"
+		maxReplicas, maxNonReadyPodCount = blueGreenReplicas(spec.Strategy.BlueGreen, replicas)
+	case spec.Strategy.Canary != nil:
+		strategy = "Canary"
+
+		maxReplicas, maxNonReadyPodCount, err = canaryReplicas(spec.Strategy.Canary, replicas)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("rollout: %s has neither a blueGreen nor a canary strategy", obj.GetName())
+	}
+
+	podResources := calcPodResources(&template.Spec)
+	rolloutResources := podResources.Containers.MulInt32(maxReplicas - maxNonReadyPodCount).Add(podResources.MaxResources.MulInt32(maxNonReadyPodCount))
+	normalResources := podResources.Containers.MulInt32(replicas)
+
+	resourceUsage := ResourceUsage{
+		NormalResources:  normalResources,
+		RolloutResources: rolloutResources,
+		Details: Details{
+			Version:       obj.GetAPIVersion(),
+			Kind:          obj.GetKind(),
+			Name:          obj.GetName(),
+			Namespace:     obj.GetNamespace(),
+			ResourcesFrom: podResources.ResourcesFrom(),
+			Replicas:      replicas,
+			Strategy:      strategy,
+			MaxReplicas:   maxReplicas,
+			Hpa:           isHpa,
+		},
+	}
+
+	return &resourceUsage, nil
+}
+
+// blueGreenReplicas returns the max amount of pods and the max amount of non ready pods during a blue-green rollout.
+// The preview ReplicaSet runs previewReplicaCount pods (all replicas by default) next to the active one and is
+// scaled to the full replicas before the active Service is switched on promotion, so both ReplicaSets run all
+// replicas at the same time, independent of the scaleDownDelaySeconds of the previously active one.
+func blueGreenReplicas(blueGreen *blueGreenStrategy, replicas int32) (maxReplicas, maxNonReadyPodCount int32) {
+	preview := replicas
+	if blueGreen.PreviewReplicaCount != nil {
+		preview = min(*blueGreen.PreviewReplicaCount, replicas)
+	}
+
+	// the preview pods start first, the remaining pods of the new ReplicaSet on promotion
+	maxNonReadyPodCount = max(preview, replicas-preview)
+
+	return 2 * replicas, maxNonReadyPodCount
+}
+
+// canaryReplicas returns the max amount of pods and the max amount of non ready pods during a canary rollout.
+// Without traffic routing, the weights are approximated by the replica counts and the rollout is bound by maxSurge
+// and maxUnavailable like a Deployment. With traffic routing, the canary ReplicaSet is scaled by the setWeight and
+// setCanaryScale steps until it is fully promoted, while the stable ReplicaSet stays fully scaled unless
+// dynamicStableScale is set.
+func canaryReplicas(canary *canaryStrategy, replicas int32) (maxReplicas, maxNonReadyPodCount int32, err error) {
+	if canary.TrafficRouting == nil {
+		return basicCanaryReplicas(canary, replicas)
+	}
+
+	scaled := func(weight int32) int32 {
+		return int32(math.Ceil(float64(weight) * float64(replicas) / 100))
+	}
+
+	var (
+		weight      int32
+		canaryScale *int32 // set by setCanaryScale, nil if the canary follows the traffic weight
+		canaryCount int32
+		stableCount = replicas
+	)
+
+	maxReplicas = replicas
+
+	scaleTo := func(newCanaryCount, newStableCount int32) {
+		// the canary is scaled up before the stable ReplicaSet is scaled down
+		maxReplicas = max(maxReplicas, newCanaryCount+stableCount)
+		maxNonReadyPodCount = max(maxNonReadyPodCount, newCanaryCount-canaryCount)
+		canaryCount, stableCount = newCanaryCount, newStableCount
+	}
+
+	for _, step := range canary.Steps {
+		switch {
+		case step.SetWeight != nil:
+			weight = *step.SetWeight
+		case step.SetCanaryScale != nil && step.SetCanaryScale.Replicas != nil:
+			canaryScale = step.SetCanaryScale.Replicas
+		case step.SetCanaryScale != nil && step.SetCanaryScale.Weight != nil:
+			scale := scaled(*step.SetCanaryScale.Weight)
+			canaryScale = &scale
+		case step.SetCanaryScale != nil:
+			canaryScale = nil
+		default:
+			continue
+		}
+
+		newStableCount := replicas
+		if canary.DynamicStableScale {
+			newStableCount = scaled(100 - weight)
+		}
+
+		newCanaryCount := scaled(weight)
+		if canaryScale != nil {
+			newCanaryCount = *canaryScale
+		}
+
+		scaleTo(newCanaryCount, newStableCount)
+	}
+
+	// the rollout ends with fully promoting the canary, the previous stable ReplicaSet is scaled down afterwards
+	if canary.DynamicStableScale {
+		scaleTo(replicas, 0)
+	} else {
+		scaleTo(replicas, replicas)
+	}
+
+	return maxReplicas, maxNonReadyPodCount, nil
+}
+
+// basicCanaryReplicas returns the max amount of pods and the max amount of non ready pods during a canary rollout
+// without traffic routing.
+func basicCanaryReplicas(canary *canaryStrategy, replicas int32) (maxReplicas, maxNonReadyPodCount int32, err error) {
+	maxSurge := intstr.FromString("25%")
+	if canary.MaxSurge != nil {
+		maxSurge = *canary.MaxSurge
+	}
+
+	maxUnavailable := intstr.FromString("25%")
+	if canary.MaxUnavailable != nil {
+		maxUnavailable = *canary.MaxUnavailable
+	}
+
+	// the absolute number is calculated by rounding up for maxSurge and down for maxUnavailable.
+	maxSurgeInt, err := intstr.GetScaledValueFromIntOrPercent(&maxSurge, int(replicas), true)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	maxUnavailableInt, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, int(replicas), false)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if maxSurgeInt > math.MaxInt32-int(replicas) || maxUnavailableInt > math.MaxInt32 {
+		return 0, 0, errors.New("canary maxSurge or maxUnavailable out of int32 boundaries")
+	}
+
+	return replicas + int32(maxSurgeInt), int32(maxSurgeInt) + min(int32(maxUnavailableInt), replicas), nil
+}
+
+// LinkWorkloadRefs sets the LinkedWorkload of every Argo Rollout to the Deployment referenced by its workloadRef,
+// matched by kind, name and namespace. The returned warnings list the Rollouts whose Deployment is not part of
+// the objects.
+func LinkWorkloadRefs(objects []ResourceObject) []string {
+	warnings := []string{}
+
+	for i, obj := range objects {
+		u, ok := obj.Object.(*unstructured.Unstructured)
+		if !ok || u.GroupVersionKind().GroupKind() != rolloutGroupKind {
+			continue
+		}
+
+		var ref v2.CrossVersionObjectReference
+
+		refMap, found, err := unstructured.NestedMap(u.Object, "spec", "workloadRef")
+		if err != nil || !found {
+			continue
+		}
+
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(refMap, &ref); err != nil {
+			warnings = append(warnings, fmt.Sprintf("Rollout %s: %s", u.GetName(), err))
+
+			continue
+		}
+
+		for _, target := range objects {
+			if isScaleTarget(u.GetNamespace(), ref, target.Object) {
+				objects[i].LinkedWorkload = target.Object
+			}
+		}
+
+		if objects[i].LinkedWorkload == nil {
+			warnings = append(warnings, fmt.Sprintf("Rollout %s: workloadRef %s %s not found", u.GetName(), ref.Kind, ref.Name))
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,138 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestRollout(t *testing.T) {
+	var tests = []struct {
+		name        string
+		rollout     string
+		strategy    string
+		replicas    int32
+		maxReplicas int32
+		cpuRequest  resource.Quantity
+		cpuLimit    resource.Quantity
+		memRequest  resource.Quantity
+		memLimit    resource.Quantity
+	}{
+		{
+			name:        "blue-green",
+			rollout:     blueGreenRollout,
+			strategy:    "BlueGreen",
+			replicas:    4,
+			maxReplicas: 8,
+			cpuRequest:  resource.MustParse("800m"),
+			cpuLimit:    resource.MustParse("1600m"),
+			memRequest:  resource.MustParse("512Mi"),
+			memLimit:    resource.MustParse("1Gi"),
+		},
+		{
+			name:        "blue-green with preview replicas",
+			rollout:     previewRollout,
+			strategy:    "BlueGreen",
+			replicas:    4,
+			maxReplicas: 8,
+			cpuRequest:  resource.MustParse("800m"),
+			cpuLimit:    resource.MustParse("1600m"),
+			memRequest:  resource.MustParse("512Mi"),
+			memLimit:    resource.MustParse("1Gi"),
+		},
+		{
+			name:        "blue-green with preview replicas and the default scale down delay",
+			rollout:     delayedPreviewRollout,
+			strategy:    "BlueGreen",
+			replicas:    4,
+			maxReplicas: 8,
+			cpuRequest:  resource.MustParse("800m"),
+			cpuLimit:    resource.MustParse("1600m"),
+			memRequest:  resource.MustParse("512Mi"),
+			memLimit:    resource.MustParse("1Gi"),
+		},
+		{
+			name:        "canary",
+			rollout:     canaryRollout,
+			strategy:    "Canary",
+			replicas:    10,
+			maxReplicas: 13,
+			cpuRequest:  resource.MustParse("1300m"),
+			cpuLimit:    resource.MustParse("2600m"),
+			memRequest:  resource.MustParse("832Mi"),
+			memLimit:    resource.MustParse("1664Mi"),
+		},
+		{
+			name:        "canary with traffic routing",
+			rollout:     trafficRoutingRollout,
+			strategy:    "Canary",
+			replicas:    10,
+			maxReplicas: 15,
+			cpuRequest:  resource.MustParse("1500m"),
+			cpuLimit:    resource.MustParse("3"),
+			memRequest:  resource.MustParse("960Mi"),
+			memLimit:    resource.MustParse("1920Mi"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+
+			objects := decodeObjects(r, test.rollout)
+
+			usage, err := ResourceQuotaFromYaml(objects[0])
+			r.NoError(err)
+			r.Equal("Rollout", usage.Details.Kind)
+			r.Equal(test.strategy, usage.Details.Strategy)
+			r.Equal(test.replicas, usage.Details.Replicas)
+			r.Equal(test.maxReplicas, usage.Details.MaxReplicas)
+
+			AssertEqualQuantities(r, test.cpuRequest, *usage.RolloutResources.Requests.Cpu(), "cpu request value")
+			AssertEqualQuantities(r, test.cpuLimit, *usage.RolloutResources.Limits.Cpu(), "cpu limit value")
+			AssertEqualQuantities(r, test.memRequest, *usage.RolloutResources.Requests.Memory(), "memory request value")
+			AssertEqualQuantities(r, test.memLimit, *usage.RolloutResources.Limits.Memory(), "memory limit value")
+		})
+	}
+}
+
+func TestCanaryReplicas(t *testing.T) {
+	r := require.New(t)
+
+	weight := int32(50)
+	canary := canaryStrategy{
+		TrafficRouting: map[string]any{"nginx": map[string]any{}},
+		Steps:          []canaryStep{{SetWeight: &weight}},
+	}
+
+	// the stable ReplicaSet stays fully scaled until the canary is promoted
+	maxReplicas, maxNonReadyPodCount, err := canaryReplicas(&canary, 10)
+	r.NoError(err)
+	r.Equal(int32(20), maxReplicas)
+	r.Equal(int32(5), maxNonReadyPodCount)
+}
+
+func TestRolloutWorkloadRef(t *testing.T) {
+	r := require.New(t)
+
+	objects := decodeObjects(r, workloadRefRollout, normalDeployment)
+
+	warnings := LinkWorkloadRefs(objects)
+	r.Empty(warnings)
+	r.Equal(objects[1].Object, objects[0].LinkedWorkload)
+
+	usage, err := ResourceQuotaFromYaml(objects[0])
+	r.NoError(err)
+	r.Equal(int32(4), usage.Details.MaxReplicas)
+
+	AssertEqualQuantities(r, resource.MustParse("500m"), *usage.NormalResources.Requests.Cpu(), "cpu request value")
+	AssertEqualQuantities(r, resource.MustParse("1"), *usage.RolloutResources.Requests.Cpu(), "cpu request value")
+
+	objects = decodeObjects(r, workloadRefRollout)
+	r.Equal([]string{"Rollout normal-rollout: workloadRef Deployment normal not found"}, LinkWorkloadRefs(objects))
+
+	// the Rollout is skipped after the warning
+	_, err = ResourceQuotaFromYaml(objects[0])
+	r.ErrorIs(err, ErrResourceNotSupported)
+}
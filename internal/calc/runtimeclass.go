@@ -0,0 +1,33 @@
+package calc
+
+import (
+	v1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+)
+
+// ApplyRuntimeClasses sets the overhead of the RuntimeClass referenced by the pod template of a copy of the object,
+// just like the RuntimeClass admission controller does. An overhead already set in the pod template is kept.
+func ApplyRuntimeClasses(resourceObject ResourceObject, runtimeClasses []*nodev1.RuntimeClass) ResourceObject {
+	if updated, ok := updatePodSpecs(resourceObject, func(spec *v1.PodSpec) {
+		applyRuntimeClass(spec, runtimeClasses)
+	}); ok {
+		return updated
+	}
+
+	return resourceObject
+}
+
+// applyRuntimeClass sets the overhead of the RuntimeClass referenced by the pod spec.
+func applyRuntimeClass(spec *v1.PodSpec, runtimeClasses []*nodev1.RuntimeClass) {
+	if spec.RuntimeClassName == nil || spec.Overhead != nil {
+		return
+	}
+
+	for _, runtimeClass := range runtimeClasses {
+		if runtimeClass.Name == *spec.RuntimeClassName && runtimeClass.Overhead != nil {
+			spec.Overhead = addResourceList(nil, runtimeClass.Overhead.PodFixed)
+
+			return
+		}
+	}
+}
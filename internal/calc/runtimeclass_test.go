@@ -0,0 +1,47 @@
+package calc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestApplyRuntimeClasses(t *testing.T) {
+	r := require.New(t)
+
+	podObject, kind, version, err := ConvertToRuntimeObjectFromYaml([]byte(kataPod), false)
+	r.NoError(err)
+
+	runtimeClassObject, _, _, err := ConvertToRuntimeObjectFromYaml([]byte(kataRuntimeClass), false)
+	r.NoError(err)
+
+	runtimeClass, ok := runtimeClassObject.(*nodev1.RuntimeClass)
+	r.True(ok)
+
+	object := ResourceObject{Object: podObject, Kind: *kind, Version: *version}
+
+	usage, err := ResourceQuotaFromYaml(ApplyRuntimeClasses(object, []*nodev1.RuntimeClass{runtimeClass}))
+	r.NoError(err)
+	AssertEqualQuantities(r, resource.MustParse("750m"), *usage.NormalResources.Requests.Cpu(), "cpu request value")
+	AssertEqualQuantities(r, resource.MustParse("1250m"), *usage.NormalResources.Limits.Cpu(), "cpu limit value")
+	AssertEqualQuantities(r, resource.MustParse("416Mi"), *usage.NormalResources.Requests.Memory(), "memory request value")
+	r.NotContains(usage.NormalResources.Limits, v1.ResourceMemory)
+
+	// without the RuntimeClass, the overhead is unknown
+	usage, err = ResourceQuotaFromYaml(object)
+	r.NoError(err)
+	AssertEqualQuantities(r, resource.MustParse("500m"), *usage.NormalResources.Requests.Cpu(), "original cpu request value")
+
+	// an overhead set in the pod is kept
+	pod, ok := podObject.(*v1.Pod)
+	r.True(ok)
+	pod.Spec.Overhead = v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")}
+
+	usage, err = ResourceQuotaFromYaml(ApplyRuntimeClasses(object, []*nodev1.RuntimeClass{runtimeClass}))
+	r.NoError(err)
+	AssertEqualQuantities(r, resource.MustParse("600m"), *usage.NormalResources.Requests.Cpu(), "pod overhead cpu request value")
+	AssertEqualQuantities(r, resource.MustParse("256Mi"), *usage.NormalResources.Requests.Memory(), "pod overhead memory request value")
+}
@@ -5,11 +5,16 @@ import (
 	"math"
 
 	appsv1 "k8s.io/api/apps/v1"
+	v2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // calculates the cpu/memory resources a single statefulset needs. Replicas are taken into account.
-func statefulSet(s appsv1.StatefulSet) (*ResourceUsage, error) {
+// Each replica gets its own claim of every volumeClaimTemplate. The rollout resources cover the most expensive of an
+// update, where only the pods at or above the partition are replaced, and a scale up from zero, where the Parallel
+// podManagementPolicy starts all pods with their init containers at once.
+func statefulSet(s appsv1.StatefulSet, hpa *v2.HorizontalPodAutoscaler, hpaMode HpaMode) (*ResourceUsage, error) {
 	var (
 		replicas       int32
 		maxUnavailable int32
@@ -24,6 +29,8 @@ func statefulSet(s appsv1.StatefulSet) (*ResourceUsage, error) {
 		replicas = 1
 	}
 
+	replicas, isHpa := hpaReplicas(replicas, hpa, hpaMode)
+
 	// https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#update-strategies
 	switch strategy.Type {
 	case appsv1.OnDeleteStatefulSetStrategyType:
@@ -32,55 +39,82 @@ func statefulSet(s appsv1.StatefulSet) (*ResourceUsage, error) {
 		maxUnavailable = replicas
 	case "":
 		// RollingUpdate is the default and can be an empty string. If so, set the defaults and continue calculation.
-		defaultMaxUnavailable := intstr.FromInt32(1)
-		strategy = appsv1.StatefulSetUpdateStrategy{
-			Type: appsv1.RollingUpdateStatefulSetStrategyType,
-			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
-				MaxUnavailable: &defaultMaxUnavailable,
-			},
-		}
+		strategy = appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType}
 
 		fallthrough
 	case appsv1.RollingUpdateStatefulSetStrategyType:
-		// RollingUpdate updates each Pod one at a time. It waits until an updated Pod is Running and Ready before continuing with the next pod.
-		// There is an alpha feature to support rollout of multiple pods at once with `.spec.updateStrategy.rollingUpdate.maxUnavailable`
-		var maxUnavailableValue intstr.IntOrString
-
-		if strategy.RollingUpdate == nil {
-			maxUnavailableValue = intstr.FromInt32(1)
-		} else {
-			maxUnavailableValue = *strategy.RollingUpdate.MaxUnavailable
-		}
+		var err error
 
-		// docs say, that the absolute number is calculated by rounding up.
-		maxUnavailableInt, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailableValue, int(replicas), true)
+		maxUnavailable, err = statefulSetRollingUpdate(strategy.RollingUpdate, replicas)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		if maxUnavailableInt < math.MinInt32 || maxUnavailableInt > math.MaxInt32 {
-			return nil, errors.New("maxUnavailableInt out of int32 boundaries")
-		}
-
-		maxUnavailable = int32(maxUnavailableInt)
+	// OrderedReady starts one pod after the other, while Parallel starts all pods at once
+	startingPods := min(1, replicas)
+	if s.Spec.PodManagementPolicy == appsv1.ParallelPodManagement {
+		startingPods = replicas
 	}
 
+	nonReady := max(maxUnavailable, startingPods)
+
 	podResources := calcPodResources(&s.Spec.Template.Spec)
-	rolloutResources := podResources.Containers.MulInt32(replicas - maxUnavailable).Add(podResources.MaxResources.MulInt32(maxUnavailable))
+	rolloutResources := podResources.Containers.MulInt32(replicas - nonReady).Add(podResources.MaxResources.MulInt32(nonReady))
 	normalResources := podResources.Containers.MulInt32(replicas)
 
+	storage := v1.ResourceList{}
+	for i := range s.Spec.VolumeClaimTemplates {
+		storage = addResourceList(storage, claimStorage(&s.Spec.VolumeClaimTemplates[i].Spec))
+	}
+
 	resourceUsage := ResourceUsage{
 		NormalResources:  normalResources,
 		RolloutResources: rolloutResources,
+		Storage:          mulResourceList(storage, float64(replicas)),
 		Details: Details{
-			Version:     s.APIVersion,
-			Kind:        s.Kind,
-			Name:        s.Name,
-			Replicas:    replicas,
-			Strategy:    string(strategy.Type),
-			MaxReplicas: replicas,
+			Version:       s.APIVersion,
+			Kind:          s.Kind,
+			Name:          s.Name,
+			Namespace:     s.Namespace,
+			ResourcesFrom: podResources.ResourcesFrom(),
+			Replicas:      replicas,
+			Strategy:      string(strategy.Type),
+			MaxReplicas:   replicas,
+			Hpa:           isHpa,
 		},
 	}
 
 	return &resourceUsage, nil
 }
+
+// statefulSetRollingUpdate returns the number of pods replaced at the same time by a rolling update. Only the pods
+// with an ordinal at or above the partition are updated. RollingUpdate updates each Pod one at a time, unless the
+// alpha feature `.spec.updateStrategy.rollingUpdate.maxUnavailable` is used. Like in the StatefulSet controller, a
+// percentage is rounded down, but at least one pod is replaced.
+func statefulSetRollingUpdate(rollingUpdate *appsv1.RollingUpdateStatefulSetStrategy, replicas int32) (int32, error) {
+	var partition int32
+
+	maxUnavailableValue := intstr.FromInt32(1)
+
+	if rollingUpdate != nil && rollingUpdate.Partition != nil {
+		partition = *rollingUpdate.Partition
+	}
+
+	if rollingUpdate != nil && rollingUpdate.MaxUnavailable != nil {
+		maxUnavailableValue = *rollingUpdate.MaxUnavailable
+	}
+
+	maxUnavailableInt, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailableValue, int(replicas), false)
+	if err != nil {
+		return 0, err
+	}
+
+	if maxUnavailableInt < math.MinInt32 || maxUnavailableInt > math.MaxInt32 {
+		return 0, errors.New("maxUnavailableInt out of int32 boundaries")
+	}
+
+	updatedPods := max(0, replicas-partition)
+
+	return min(max(1, int32(maxUnavailableInt)), updatedPods), nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
@@ -42,6 +43,39 @@ func TestStatefulSet(t *testing.T) {
 			maxReplicas: 1,
 			strategy:    appsv1.RollingUpdateStatefulSetStrategyType,
 		},
+		{
+			name:        "maxUnavailable percentage rounded down",
+			statefulset: percentStatefulSet,
+			cpuMin:      resource.MustParse("4"),
+			cpuMax:      resource.MustParse("12"),
+			memoryMin:   resource.MustParse("24Gi"),
+			memoryMax:   resource.MustParse("48Gi"),
+			replicas:    10,
+			maxReplicas: 10,
+			strategy:    appsv1.RollingUpdateStatefulSetStrategyType,
+		},
+		{
+			name:        "partition",
+			statefulset: partitionStatefulSet,
+			cpuMin:      resource.MustParse("1750m"),
+			cpuMax:      resource.MustParse("5"),
+			memoryMin:   resource.MustParse("10Gi"),
+			memoryMax:   resource.MustParse("20Gi"),
+			replicas:    4,
+			maxReplicas: 4,
+			strategy:    appsv1.RollingUpdateStatefulSetStrategyType,
+		},
+		{
+			name:        "scale from zero with parallel pod management",
+			statefulset: parallelStatefulSet,
+			cpuMin:      resource.MustParse("3"),
+			cpuMax:      resource.MustParse("6"),
+			memoryMin:   resource.MustParse("12Gi"),
+			memoryMax:   resource.MustParse("24Gi"),
+			replicas:    3,
+			maxReplicas: 3,
+			strategy:    appsv1.RollingUpdateStatefulSetStrategyType,
+		},
 	}
 
 	for _, test := range tests {
@@ -50,17 +84,32 @@ func TestStatefulSet(t *testing.T) {
 
 			resourceObject, kind, version, _ := ConvertToRuntimeObjectFromYaml([]byte(test.statefulset), false)
 
-			usage, err := ResourceQuotaFromYaml(ResourceObject{resourceObject, *kind, *version, nil})
+			usage, err := ResourceQuotaFromYaml(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version})
 			r.NoError(err)
 			r.NotEmpty(usage)
 
-			AssertEqualQuantities(r, test.cpuMin, usage.RolloutResources.CPUMin, "cpu request value")
-			AssertEqualQuantities(r, test.cpuMax, usage.RolloutResources.CPUMax, "cpu limit value")
-			AssertEqualQuantities(r, test.memoryMin, usage.RolloutResources.MemoryMin, "memory request value")
-			AssertEqualQuantities(r, test.memoryMax, usage.RolloutResources.MemoryMax, "memory limit value")
+			AssertEqualQuantities(r, test.cpuMin, *usage.RolloutResources.Requests.Cpu(), "cpu request value")
+			AssertEqualQuantities(r, test.cpuMax, *usage.RolloutResources.Limits.Cpu(), "cpu limit value")
+			AssertEqualQuantities(r, test.memoryMin, *usage.RolloutResources.Requests.Memory(), "memory request value")
+			AssertEqualQuantities(r, test.memoryMax, *usage.RolloutResources.Limits.Memory(), "memory limit value")
 			r.Equalf(test.replicas, usage.Details.Replicas, "replicas")
 			r.Equalf(test.maxReplicas, usage.Details.MaxReplicas, "maxReplicas")
 			r.Equalf(string(test.strategy), usage.Details.Strategy, "strategy")
 		})
 	}
 }
+
+func TestStatefulSetStorage(t *testing.T) {
+	r := require.New(t)
+
+	resourceObject, kind, version, _ := ConvertToRuntimeObjectFromYaml([]byte(volumeClaimTemplatesStatefulSet), false)
+
+	usage, err := ResourceQuotaFromYaml(ResourceObject{Object: resourceObject, Kind: *kind, Version: *version})
+	r.NoError(err)
+
+	r.Len(usage.Storage, 4)
+	AssertEqualQuantities(r, resource.MustParse("33Gi"), usage.Storage[v1.ResourceRequestsStorage], "storage request value")
+	AssertEqualQuantities(r, resource.MustParse("6"), usage.Storage[v1.ResourcePersistentVolumeClaims], "claims value")
+	AssertEqualQuantities(r, resource.MustParse("30Gi"), usage.Storage["gold.storageclass.storage.k8s.io/requests.storage"], "gold storage request value")
+	AssertEqualQuantities(r, resource.MustParse("3"), usage.Storage["gold.storageclass.storage.k8s.io/persistentvolumeclaims"], "gold claims value")
+}
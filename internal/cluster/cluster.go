@@ -0,0 +1,169 @@
+// Package cluster provides functions to read the resources kuota-calc supports from a running cluster.
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	openshiftAppsV1 "github.com/openshift/api/apps/v1"
+	openshiftApps "github.com/openshift/client-go/apps/clientset/versioned"
+	appsv1 "k8s.io/api/apps/v1"
+	v2 "k8s.io/api/autoscaling/v2"
+	batchV1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/bgruszka/kuota-calc/internal/calc"
+)
+
+// Lister lists resources of a cluster. OpenShift is optional, if nil no DeploymentConfigs are listed.
+type Lister struct {
+	Kubernetes kubernetes.Interface
+	OpenShift  openshiftApps.Interface
+}
+
+// List returns all Deployments, StatefulSets, ReplicaSets, ReplicationControllers, DaemonSets, Jobs, CronJobs, Pods,
+// DeploymentConfigs, HorizontalPodAutoscalers, ResourceQuotas and LimitRanges of the given namespace. An empty
+// namespace lists all namespaces. The cluster scoped RuntimeClasses and Nodes are always listed, as they supply the
+// pod overhead and the nodes DaemonSets are scheduled to. Both are skipped if listing them is forbidden,
+// DeploymentConfigs if the cluster does not serve them. Objects managed by a controller, like the Pods of a
+// Deployment, are listed as well, see calc.DeduplicateOwned.
+func (l Lister) List(ctx context.Context, namespace string) ([]calc.ResourceObject, error) { //nolint:funlen // one block per kind
+	objects := []calc.ResourceObject{}
+	opts := metav1.ListOptions{}
+
+	deployments, err := l.Kubernetes.AppsV1().Deployments(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing deployments: %w", err)
+	}
+
+	objects = appendObjects(objects, deployments.Items, appsv1.SchemeGroupVersion.WithKind("Deployment"))
+
+	statefulSets, err := l.Kubernetes.AppsV1().StatefulSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing statefulsets: %w", err)
+	}
+
+	objects = appendObjects(objects, statefulSets.Items, appsv1.SchemeGroupVersion.WithKind("StatefulSet"))
+
+	replicaSets, err := l.Kubernetes.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing replicasets: %w", err)
+	}
+
+	objects = appendObjects(objects, replicaSets.Items, appsv1.SchemeGroupVersion.WithKind("ReplicaSet"))
+
+	replicationControllers, err := l.Kubernetes.CoreV1().ReplicationControllers(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing replicationcontrollers: %w", err)
+	}
+
+	objects = appendObjects(objects, replicationControllers.Items, v1.SchemeGroupVersion.WithKind("ReplicationController"))
+
+	daemonSets, err := l.Kubernetes.AppsV1().DaemonSets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing daemonsets: %w", err)
+	}
+
+	objects = appendObjects(objects, daemonSets.Items, appsv1.SchemeGroupVersion.WithKind("DaemonSet"))
+
+	jobs, err := l.Kubernetes.BatchV1().Jobs(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing jobs: %w", err)
+	}
+
+	objects = appendObjects(objects, jobs.Items, batchV1.SchemeGroupVersion.WithKind("Job"))
+
+	cronJobs, err := l.Kubernetes.BatchV1().CronJobs(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing cronjobs: %w", err)
+	}
+
+	objects = appendObjects(objects, cronJobs.Items, batchV1.SchemeGroupVersion.WithKind("CronJob"))
+
+	pods, err := l.Kubernetes.CoreV1().Pods(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing pods: %w", err)
+	}
+
+	objects = appendObjects(objects, pods.Items, v1.SchemeGroupVersion.WithKind("Pod"))
+
+	hpas, err := l.Kubernetes.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing horizontalpodautoscalers: %w", err)
+	}
+
+	objects = appendObjects(objects, hpas.Items, v2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"))
+
+	quotas, err := l.Kubernetes.CoreV1().ResourceQuotas(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing resourcequotas: %w", err)
+	}
+
+	objects = appendObjects(objects, quotas.Items, v1.SchemeGroupVersion.WithKind("ResourceQuota"))
+
+	limitRanges, err := l.Kubernetes.CoreV1().LimitRanges(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing limitranges: %w", err)
+	}
+
+	objects = appendObjects(objects, limitRanges.Items, v1.SchemeGroupVersion.WithKind("LimitRange"))
+
+	runtimeClasses, err := l.Kubernetes.NodeV1().RuntimeClasses().List(ctx, opts)
+
+	switch {
+	case apierrors.IsForbidden(err):
+		// namespace users may not be allowed to list cluster scoped resources, no overhead is added then
+	case err != nil:
+		return nil, fmt.Errorf("listing runtimeclasses: %w", err)
+	default:
+		objects = appendObjects(objects, runtimeClasses.Items, nodev1.SchemeGroupVersion.WithKind("RuntimeClass"))
+	}
+
+	nodes, err := l.Kubernetes.CoreV1().Nodes().List(ctx, opts)
+
+	switch {
+	case apierrors.IsForbidden(err):
+		// namespace users are usually not allowed to list nodes, DaemonSets are calculated as a single pod then
+	case err != nil:
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	default:
+		objects = appendObjects(objects, nodes.Items, v1.SchemeGroupVersion.WithKind("Node"))
+	}
+
+	if l.OpenShift != nil {
+		deploymentConfigs, err := l.OpenShift.AppsV1().DeploymentConfigs(namespace).List(ctx, opts)
+
+		switch {
+		case apierrors.IsNotFound(err):
+			// not an openshift cluster
+		case err != nil:
+			return nil, fmt.Errorf("listing deploymentconfigs: %w", err)
+		default:
+			objects = appendObjects(objects, deploymentConfigs.Items, openshiftAppsV1.SchemeGroupVersion.WithKind("DeploymentConfig"))
+		}
+	}
+
+	return objects, nil
+}
+
+// appendObjects converts the items of a list to ResourceObjects. The kind and version are set on every
+// item, as the API server does not return them for list items.
+func appendObjects[T any, PT interface {
+	*T
+	runtime.Object
+}](objects []calc.ResourceObject, items []T, gvk schema.GroupVersionKind) []calc.ResourceObject {
+	for i := range items {
+		var obj PT = &items[i]
+
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+		objects = append(objects, calc.ResourceObject{Object: obj, Kind: gvk.Kind, Version: gvk.Version})
+	}
+
+	return objects
+}
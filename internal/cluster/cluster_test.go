@@ -0,0 +1,131 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	openshiftAppsV1 "github.com/openshift/api/apps/v1"
+	openshiftFake "github.com/openshift/client-go/apps/clientset/versioned/fake"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v2 "k8s.io/api/autoscaling/v2"
+	batchV1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	nodev1 "k8s.io/api/node/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/bgruszka/kuota-calc/internal/calc"
+)
+
+func kinds(objects []calc.ResourceObject) []string {
+	result := []string{}
+	for _, obj := range objects {
+		result = append(result, obj.Kind)
+	}
+
+	return result
+}
+
+func TestList(t *testing.T) {
+	r := require.New(t)
+
+	replicas := int32(2)
+	controller := true
+	client := fake.NewSimpleClientset(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-a"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "team-a"}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "team-a"}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "team-b"}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:            "app-7d9c5b8f4-x2k8l",
+			Namespace:       "team-a",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "app-7d9c5b8f4", Controller: &controller}},
+		}},
+		&batchV1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:            "backup-29000000",
+			Namespace:       "team-a",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "batch/v1", Kind: "CronJob", Name: "backup", Controller: &controller}},
+		}},
+		&v2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-a"}},
+		&v1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "team-a"}},
+		&nodev1.RuntimeClass{ObjectMeta: metav1.ObjectMeta{Name: "kata"}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
+	)
+	openshift := openshiftFake.NewSimpleClientset(
+		&openshiftAppsV1.DeploymentConfig{ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "team-a"}},
+	)
+
+	lister := Lister{Kubernetes: client, OpenShift: openshift}
+
+	objects, err := lister.List(context.Background(), "team-a")
+	r.NoError(err)
+	// owned objects are deduplicated after listing
+	r.Equal([]string{"Deployment", "StatefulSet", "ReplicaSet", "Job", "Pod", "HorizontalPodAutoscaler", "LimitRange", "RuntimeClass", "Node", "DeploymentConfig"}, kinds(objects))
+
+	deployment, ok := objects[0].Object.(*appsv1.Deployment)
+	r.True(ok)
+	r.Equal("apps/v1", deployment.APIVersion)
+	r.Equal("Deployment", deployment.Kind)
+	r.Equal("v1", objects[0].Version)
+
+	usage, err := calc.ResourceQuotaFromYaml(objects[0])
+	r.NoError(err)
+	r.Equal("Deployment", usage.Details.Kind)
+	r.Equal(int32(2), usage.Details.Replicas)
+
+	objects, err = lister.List(context.Background(), "")
+	r.NoError(err)
+	r.Equal([]string{"Deployment", "StatefulSet", "ReplicaSet", "Job", "Pod", "Pod", "HorizontalPodAutoscaler", "LimitRange", "RuntimeClass", "Node", "DeploymentConfig"}, kinds(objects))
+}
+
+func TestListWithoutDeploymentConfigs(t *testing.T) {
+	r := require.New(t)
+
+	openshift := openshiftFake.NewSimpleClientset()
+	openshift.PrependReactor("list", "deploymentconfigs", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(openshiftAppsV1.Resource("deploymentconfigs"), "")
+	})
+
+	lister := Lister{Kubernetes: fake.NewSimpleClientset(), OpenShift: openshift}
+
+	objects, err := lister.List(context.Background(), "team-a")
+	r.NoError(err)
+	r.Empty(objects)
+}
+
+func TestListWithoutNodes(t *testing.T) {
+	r := require.New(t)
+
+	client := fake.NewSimpleClientset(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}})
+	client.PrependReactor("list", "nodes", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(v1.Resource("nodes"), "", nil)
+	})
+
+	lister := Lister{Kubernetes: client}
+
+	objects, err := lister.List(context.Background(), "team-a")
+	r.NoError(err)
+	r.Empty(objects)
+}
+
+func TestListWithoutRuntimeClasses(t *testing.T) {
+	r := require.New(t)
+
+	client := fake.NewSimpleClientset(&nodev1.RuntimeClass{ObjectMeta: metav1.ObjectMeta{Name: "kata"}})
+	client.PrependReactor("list", "runtimeclasses", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(nodev1.Resource("runtimeclasses"), "", nil)
+	})
+
+	lister := Lister{Kubernetes: client}
+
+	objects, err := lister.List(context.Background(), "team-a")
+	r.NoError(err)
+	r.Empty(objects)
+}
@@ -2,6 +2,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	"github.com/bgruszka/kuota-calc/cmd"
@@ -16,6 +17,8 @@ var (
 
 const (
 	binaryName = "kuota-calc"
+
+	exitCodeQuotaExceeded = 2
 )
 
 func main() {
@@ -30,6 +33,15 @@ func main() {
 
 	root := cmd.NewKuotaCalcCmd(&v, genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
 	if err := root.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
+
+// exitCode returns the exit code of a failed command, 2 if a ResourceQuota is exceeded in check mode.
+func exitCode(err error) int {
+	if errors.Is(err, cmd.ErrQuotaExceeded) {
+		return exitCodeQuotaExceeded
+	}
+
+	return 1
+}
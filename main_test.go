@@ -0,0 +1,18 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bgruszka/kuota-calc/cmd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal(2, exitCode(cmd.ErrQuotaExceeded))
+	r.Equal(2, exitCode(fmt.Errorf("check: %w", cmd.ErrQuotaExceeded)))
+	r.Equal(1, exitCode(errors.New("check: no ResourceQuota found in input or quota file")))
+}